@@ -14,15 +14,66 @@ const (
 	FormH string = "%d.%d"                      // Shorthand SemVer (e.g., 1.24)
 	FormI string = "v%d"                        // v# (v1 -> v100)
 	FormJ string = "v%d.%d"                     // v#.# (v1.1 -> v100.100)
+	FormK string = "%d.%d.%d.%d"                // Four Part .NET Version (no v-prefix)
+	FormL string = "v%d.%d.%d.%d"               // Four Part Prefix Version
 
-	FileVersion     string = "VERSION"      // Full Contents Replaced
-	FilePackageJson string = "package.json" // Key "version" Replaced
-	FileMavenPom    string = "pom.xml"      // Key "version" Replaced
-	FileHelmChart   string = "Chart.yaml"   // Key "version" Replaced
-	FileDockerfile  string = "Dockerfile"   // Label "version" Replaced
-	FileGoMod       string = "go.mod"       // Line 3, aka "go #.#[.#]" Replaced
+	FileVersion     string = "VERSION"        // Full Contents Replaced
+	FilePackageJson string = "package.json"   // Key "version" Replaced
+	FileMavenPom    string = "pom.xml"        // Key "version" Replaced
+	FileHelmChart   string = "Chart.yaml"     // Key "version" Replaced
+	FileDockerfile  string = "Dockerfile"     // LABEL, ARG or ENV "version" Replaced
+	FileGoMod       string = "go.mod"         // Line 3, aka "go #.#[.#]" Replaced
+	FileCargoToml   string = "Cargo.toml"     // Key "version" in [package] or [workspace.package] Replaced
+	FilePyProject   string = "pyproject.toml" // Key "version" in [project] or [tool.poetry] Replaced
+	FileSetupCfg    string = "setup.cfg"      // Key "version" in [metadata] Replaced
+	FilePython      string = "*.py"           // Assignment __version__ = "..." Replaced
+
+	FileGradleProperties string = "gradle.properties"     // Property version= Replaced
+	FileBuildGradle      string = "build.gradle"          // Assignment version = "..." Replaced
+	FileBuildGradleKts   string = "build.gradle.kts"      // Assignment version = "..." Replaced
+	FileCsproj           string = "*.csproj"              // Element <Version> or <VersionPrefix>/<VersionSuffix> Replaced
+	FileBuildProps       string = "Directory.Build.props" // Element <Version> or <VersionPrefix>/<VersionSuffix> Replaced
+	FileAssemblyInfo     string = "AssemblyInfo.cs"       // Attributes AssemblyVersion/AssemblyFileVersion Replaced
+
+	AppVersionIgnore string = "ignore" // Chart.yaml "version" is read and bumped, "appVersion" is left untouched
+	AppVersionSync   string = "sync"   // Chart.yaml "appVersion" is replaced with the new "version" in lockstep
+	AppVersionDerive string = "derive" // Chart.yaml "appVersion" receives the same bumps as "version" from its own value
+	AppVersionOnly   string = "only"   // Chart.yaml "appVersion" is read and bumped, "version" is left untouched
+
+	GoSourceAuto      string = "auto"      // go.mod "go" is saved from igo when installed, otherwise from GoSourceLocal
+	GoSourceLocal     string = "local"     // go.mod "go" is saved from `go env GOVERSION` of the local toolchain
+	GoSourceToolchain string = "toolchain" // go.mod "go" is saved from its "toolchain" directive
+	GoSourceIgo       string = "igo"       // go.mod "go" is saved from ~/go/version of igo
+
+	PomProject string = "project" // pom.xml <project><version> is read and bumped
+	PomParent  string = "parent"  // pom.xml <project><parent><version> is read and bumped
+
+	WorkspaceNpm   string = "npm"   // package.json members of the "workspaces" (or pnpm-workspace.yaml) of the root
+	WorkspaceCargo string = "cargo" // Cargo.toml members of the [workspace] of the root
+	WorkspaceGo    string = "go"    // go.mod of every module, versioned by the DIR/vX.Y.Z git tags of its directory
+	WorkspaceHelm  string = "helm"  // Chart.yaml of every chart, with the file:// dependencies between them
+
+	LevelMajor string = "major" // commit implies a major bump, such as a breaking change
+	LevelMinor string = "minor" // commit implies a minor bump, such as a feat
+	LevelPatch string = "patch" // commit implies a patch bump, such as a fix
+	LevelNone  string = "none"  // commit implies no bump, such as a docs or chore
 )
 
+// AppVersionPolicies can be passed into SetAppVersionPolicy
+var AppVersionPolicies = []string{AppVersionIgnore, AppVersionSync, AppVersionDerive, AppVersionOnly}
+
+// GoSources can be passed into SetGoSource, along with an explicit Go version such as 1.24.5
+var GoSources = []string{GoSourceAuto, GoSourceLocal, GoSourceToolchain, GoSourceIgo}
+
+// PomTargets can be passed into SetPomTarget
+var PomTargets = []string{PomProject, PomParent}
+
+// Levels are the bump levels of a Commit from highest to lowest
+var Levels = []string{LevelMajor, LevelMinor, LevelPatch, LevelNone}
+
+// CommitTypes maps the Conventional Commits types to their bump level by default; any other type implies LevelNone
+var CommitTypes = map[string]string{"feat": LevelMinor, "fix": LevelPatch, "perf": LevelPatch}
+
 // SupportedFiles can be passed into `-in` when running bump
 var SupportedFiles = []string{
 	FileVersion,
@@ -31,25 +82,79 @@ var SupportedFiles = []string{
 	FileHelmChart,
 	FileDockerfile,
 	FileGoMod,
+	FileCargoToml,
+	FilePyProject,
+	FileSetupCfg,
+	FilePython,
+	FileGradleProperties,
+	FileBuildGradle,
+	FileBuildGradleKts,
+	FileCsproj,
+	FileBuildProps,
+	FileAssemblyInfo,
 }
 
 var (
 	reTwoPart   = regexp.MustCompile(`^(\d+)\.(\d+)$`)        // Two Part Version Only
 	reThreePart = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)$`) // Three Part Version Only
 
+	// SemVer 2.0.0 with an optional "v" prefix, dot-separated pre-release identifiers and build metadata
+	reSemVer = regexp.MustCompile(`^(v?)(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+		`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+		`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+	// SemVer 2.0.0 single pre-release identifier (numeric without leading zeros or alphanumeric)
+	reIdentifier = regexp.MustCompile(`^(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)$`)
+	// SemVer 2.0.0 single build metadata identifier (leading zeros allowed)
+	reBuildIdentifier = regexp.MustCompile(`^[0-9a-zA-Z-]+$`)
+
 	// Regex for file-specific parsing/saving
 
-	// Dockerfile Label
-	reDockerfileVersion = regexp.MustCompile(`(LABEL\s+(?:org\.label-schema\.version|version)=")([^"]+)(")`)
 	// Go Mod Version
-	reGoModVersion      = regexp.MustCompile(`(go\s+)([0-9.]+)`)
-	// Maven Version
-	reMavenVersion      = regexp.MustCompile(`(?s)(<project.*?>.*?<version>)(.*?)(</version>)`)
+	reGoModVersion = regexp.MustCompile(`(?m)^(go[ \t]+)([0-9.]+)`)
+	// Go Mod Toolchain
+	reGoModToolchain = regexp.MustCompile(`(?m)^(toolchain[ \t]+go)([0-9.]+)`)
+	// Go Version of a source such as go env GOVERSION (go1.24.5) or -go=1.24.5
+	reGoVersion = regexp.MustCompile(`^(?:go)?(\d+\.\d+(?:\.\d+)?)$`)
+	// Python Module __version__ Assignment
+	rePythonVersion = regexp.MustCompile(`(?m)^(\s*__version__\s*(?::\s*str\s*)?=\s*)(?:"([^"\n]+)"|'([^'\n]+)')`)
+	// setup.cfg (INI) version Key
+	reSetupCfgVersion = regexp.MustCompile(`^(\s*version\s*[=:][ \t]*)([^\s#;]+)`)
+	// PEP 440 release with a pre-release or development segment, such as 1.2.3rc1, 1.2b2 or 1.2.3.dev4
+	rePep440 = regexp.MustCompile(`(?i)^(v?)(\d+)\.(\d+)(?:\.(\d+))?` +
+		`(?:[-_.]?(a|alpha|b|beta|c|rc|pre|preview)[-_.]?(\d*))?(?:[-_.]?(dev)[-_.]?(\d*))?$`)
+	// gradle.properties version Property
+	reGradlePropertiesVersion = regexp.MustCompile(`(?m)^([ \t]*version[ \t]*[=:][ \t]*)([^\s#!]+)`)
+	// build.gradle and build.gradle.kts version Assignment
+	reBuildGradleVersion = regexp.MustCompile(`(?m)^([ \t]*version[ \t]*=?[ \t]*)(["'])([^"'\n]+)(["'])`)
+	// MSBuild <PropertyGroup> Element of a project file, the only place the <Version> properties are read from
+	reMSBuildPropertyGroup = regexp.MustCompile(`(?s)<PropertyGroup(?:\s[^>]*)?>.*?</PropertyGroup>`)
+	// MSBuild <Version>, <VersionPrefix> and <VersionSuffix> Elements, starting a line or a <PropertyGroup>
+	reMSBuildVersion       = regexp.MustCompile(`(?m)(?:^[ \t]*|<PropertyGroup(?:\s[^>]*)?>[ \t]*)(<Version>)([^<]*)(</Version>)`)
+	reMSBuildVersionPrefix = regexp.MustCompile(`(?m)^([ \t]*)(<VersionPrefix>)([^<]*)(</VersionPrefix>)`)
+	reMSBuildVersionSuffix = regexp.MustCompile(`(?m)(?:^[ \t]*|<PropertyGroup(?:\s[^>]*)?>[ \t]*|</VersionPrefix>[ \t]*)(<VersionSuffix>)([^<]*)(</VersionSuffix>)`)
+	// AssemblyInfo.cs Version Attributes
+	reAssemblyVersion              = regexp.MustCompile(`(\[assembly:\s*Assembly(?:File)?Version(?:Attribute)?\(\s*")([^"]*)(")`)
+	reAssemblyInformationalVersion = regexp.MustCompile(`(\[assembly:\s*AssemblyInformationalVersion(?:Attribute)?\(\s*")([^"]*)(")`)
+
+	// Conventional Commits Header, such as feat(api)!: drop the v1 endpoints
+	reCommitHeader = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()]*)\))?(!)?:[ \t]+\S`)
+	// Conventional Commits Breaking Change Footer
+	reBreakingChange = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE:[ \t]`)
 )
 
+// Channels orders the named pre-release channels from lowest to highest precedence. Compare ranks a pair of these
+// identifiers by their position in Channels instead of lexically, so it can be reordered to match a release process.
+var Channels = []string{"alpha", "beta", "preview", "rc"}
+
+// Ladder orders the pre-release channels that Promote advances a Version through before its final release, and that
+// SetChannel refuses to move a Version back along
+var Ladder = []string{"alpha", "beta", "rc"}
+
 // Forms is a map of format strings to the expected number of scanned items.
 var Forms = map[string]int{
 	FormE: 5, // 1:major 2:minor 3:patch 4:beta 5:alpha
+	FormL: 4, // 1:major 2:minor 3:patch 4:revision
+	FormK: 4, // 1:major 2:minor 3:patch 4:revision
 	FormB: 4, // 1:major 2:minor 3:patch 4:alpha
 	FormC: 4, // 1:major 2:minor 3:patch 4:beta
 	FormD: 4, // 1:major 2:minor 3:patch 4:rc
@@ -62,4 +167,4 @@ var Forms = map[string]int{
 }
 
 // formsInOrder defines a deterministic order for scanning, from most specific to least.
-var formsInOrder = []string{FormE, FormB, FormC, FormD, FormF, FormA, FormG, FormH, FormJ, FormI}
+var formsInOrder = []string{FormE, FormB, FormC, FormD, FormF, FormL, FormK, FormA, FormG, FormH, FormJ, FormI}
@@ -0,0 +1,160 @@
+package bump
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Release describes the new version that a changelog section is written for
+type Release struct {
+	Version string    // heading of the section, such as 1.3.0
+	Tag     string    // git tag of the version, used by the compare links, such as v1.3.0
+	Date    time.Time // date of the section
+	Commits []Commit  // commits since the previous release, used when the [Unreleased] section is empty
+}
+
+// changelogTemplate starts a CHANGELOG.md that does not exist yet, following Keep a Changelog
+const changelogTemplate = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+
+## [Unreleased]
+`
+
+// changelogGroups orders the Conventional Commits types of a generated changelog section with their heading; breaking
+// changes of any type are listed first and the commits of other types are left out
+var changelogGroups = []struct {
+	Type  string
+	Title string
+}{
+	{"feat", "Features"},
+	{"fix", "Bug Fixes"},
+	{"perf", "Performance"},
+	{"refactor", "Refactoring"},
+	{"revert", "Reverts"},
+	{"docs", "Documentation"},
+}
+
+var (
+	// Keep a Changelog [Unreleased] Heading
+	reUnreleasedHeading = regexp.MustCompile(`(?mi)^##[ \t]+\[?unreleased\]?[ \t]*\r?$`)
+	// Markdown Second Level Heading
+	reSectionHeading = regexp.MustCompile(`(?m)^##[ \t]`)
+	// Markdown Link Reference Definition, such as the compare links at the bottom of a changelog
+	reLinkDefinition = regexp.MustCompile(`(?m)^\[[^\]]+\]:[ \t]`)
+	// Keep a Changelog [Unreleased] Compare Link, such as https://github.com/o/r/compare/v1.2.3...HEAD
+	reUnreleasedLink = regexp.MustCompile(`(?mi)^(\[unreleased\]:[ \t]*)(\S+/compare/)(\S+?)\.\.\.(\S+?)[ \t]*\r?$`)
+)
+
+// ChangelogSection renders the changelog section of r from its commits, grouped by their Conventional Commits type
+//
+// Example:
+// 		section := bump.ChangelogSection(bump.Release{Version: "1.3.0", Date: time.Now(), Commits: commits})
+// 		// ## [1.3.0] - 2026-10-17
+// 		//
+// 		// ### Features
+// 		//
+// 		// - **cli:** add -auto (1a2b3c4)
+func ChangelogSection(r Release) string {
+	return changelogHeading(r) + "\n\n" + changelogNotes(r.Commits)
+}
+
+// PromoteChangelog moves the notes of the [Unreleased] section of the Keep a Changelog content under a new section of
+// r, generating them from the commits of r when the [Unreleased] section is empty or missing, and updates the compare
+// links at the bottom; empty content starts a new changelog. It returns the new content and the new section.
+//
+// Example:
+// 		content, section, err := bump.PromoteChangelog(content, bump.Release{Version: "1.3.0", Tag: "v1.3.0", Date: time.Now()})
+func PromoteChangelog(content []byte, r Release) ([]byte, string, error) {
+	text := string(content)
+	if len(strings.TrimSpace(text)) == 0 {
+		text = changelogTemplate
+	}
+	heading := regexp.MustCompile(`(?m)^##[ \t]+\[?` + regexp.QuoteMeta(r.Version) + `\]?(?:[ \t]|$)`)
+	if heading.MatchString(text) {
+		return nil, "", fmt.Errorf("changelog already has a section for %s", r.Version)
+	}
+
+	var before, unreleased, notes, after string
+	if loc := reUnreleasedHeading.FindStringIndex(text); loc != nil {
+		end := sectionEnd(text, loc[1])
+		before, unreleased = text[:loc[0]], strings.TrimRight(text[loc[0]:loc[1]], "\r")
+		notes, after = strings.TrimSpace(text[loc[1]:end]), text[end:]
+	} else {
+		end := sectionEnd(text, 0)
+		before, unreleased, after = text[:end], "## [Unreleased]", text[end:]
+		if len(before) > 0 && !strings.HasSuffix(before, "\n\n") {
+			before = strings.TrimRight(before, "\n") + "\n\n"
+		}
+	}
+	if len(notes) == 0 {
+		notes = strings.TrimSpace(changelogNotes(r.Commits))
+	}
+	section := changelogHeading(r) + "\n"
+	if len(notes) > 0 {
+		section += "\n" + notes + "\n"
+	}
+	text = before + unreleased + "\n\n" + section
+	if len(after) > 0 {
+		text += "\n" + after
+	}
+
+	if m := reUnreleasedLink.FindStringSubmatchIndex(text); m != nil && len(r.Tag) > 0 {
+		prefix, base, previous, head := text[m[2]:m[3]], text[m[4]:m[5]], text[m[6]:m[7]], text[m[8]:m[9]]
+		links := fmt.Sprintf("%s%s%s...%s\n[%s]: %s%s...%s", prefix, base, r.Tag, head, r.Version, base, previous, r.Tag)
+		text = text[:m[0]] + links + text[m[1]:]
+	}
+	return []byte(text), section, nil
+}
+
+// sectionEnd returns the offset of the next second level heading of text after offset, or of its first link reference
+// definition when there is none, or the length of text
+func sectionEnd(text string, offset int) int {
+	if loc := reSectionHeading.FindStringIndex(text[offset:]); loc != nil {
+		return offset + loc[0]
+	}
+	if loc := reLinkDefinition.FindStringIndex(text[offset:]); loc != nil {
+		return offset + loc[0]
+	}
+	return len(text)
+}
+
+// changelogHeading renders the heading of the changelog section of r
+func changelogHeading(r Release) string {
+	return fmt.Sprintf("## [%s] - %s", r.Version, r.Date.Format("2006-01-02"))
+}
+
+// changelogNotes renders the commits grouped by their Conventional Commits type as the body of a changelog section
+func changelogNotes(commits []Commit) string {
+	var out strings.Builder
+	group := func(title string, include func(Commit) bool) {
+		var entries []string
+		for _, c := range commits {
+			if !include(c) {
+				continue
+			}
+			entry := "- "
+			if len(c.Scope) > 0 {
+				entry += "**" + c.Scope + ":** "
+			}
+			entry += c.Description
+			if len(c.Hash) > 0 {
+				entry += " (" + c.Hash[:min(7, len(c.Hash))] + ")"
+			}
+			entries = append(entries, entry)
+		}
+		if len(entries) > 0 {
+			out.WriteString("### " + title + "\n\n" + strings.Join(entries, "\n") + "\n\n")
+		}
+	}
+	group("Breaking Changes", func(c Commit) bool { return c.Breaking })
+	for _, g := range changelogGroups {
+		group(g.Title, func(c Commit) bool { return !c.Breaking && c.Type == g.Type })
+	}
+	return out.String()
+}
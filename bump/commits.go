@@ -0,0 +1,98 @@
+package bump
+
+import (
+	"slices"
+	"strings"
+)
+
+// Commit is a git commit classified per Conventional Commits
+type Commit struct {
+	Hash        string `json:"hash"`
+	Subject     string `json:"subject"`
+	Type        string `json:"type,omitempty"`        // such as feat or fix, empty when the subject is not a Conventional Commit
+	Scope       string `json:"scope,omitempty"`       // such as api in feat(api): ...
+	Description string `json:"description,omitempty"` // the subject after the type and scope
+	Breaking    bool   `json:"breaking"`              // a ! after the type or a BREAKING CHANGE footer
+	Level       string `json:"level"`                 // one of Levels
+}
+
+// ParseCommit classifies the commit message with hash per Conventional Commits, using types to map its type to a level
+// (CommitTypes when nil); a breaking change is LevelMajor, except before 1.0.0 (when v is below it) where it is
+// LevelMinor
+//
+// Example:
+// 		c := bump.ParseCommit("abc1234", "feat(api)!: drop the v1 endpoints", nil, v) // LevelMajor
+func ParseCommit(hash, message string, types map[string]string, v *Version) Commit {
+	if types == nil {
+		types = CommitTypes
+	}
+	subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	c := Commit{Hash: hash, Subject: strings.TrimSpace(subject), Level: LevelNone}
+	m := reCommitHeader.FindStringSubmatch(c.Subject)
+	if m == nil {
+		return c
+	}
+	c.Type, c.Scope = strings.ToLower(m[1]), m[2]
+	_, description, _ := strings.Cut(c.Subject, ":")
+	c.Description = strings.TrimSpace(description)
+	c.Breaking = len(m[3]) > 0 || reBreakingChange.MatchString(message)
+	if level, ok := types[c.Type]; ok && slices.Contains(Levels, level) {
+		c.Level = level
+	}
+	if c.Breaking {
+		c.Level = LevelMajor
+	}
+	if c.Level == LevelMajor && v != nil && v.Major == 0 {
+		c.Level = LevelMinor
+	}
+	return c
+}
+
+// GitCommits returns the classified commits of the git repository at dir that are reachable from HEAD but not from since
+// (every commit when since is empty), newest first
+//
+// Example:
+// 		commits, err := bump.GitCommits(".", "v1.2.3", nil, v)
+func GitCommits(dir, since string, types map[string]string, v *Version) ([]Commit, error) {
+	revision := "HEAD"
+	if len(since) > 0 {
+		revision = since + "..HEAD"
+	}
+	out, err := git(dir, "log", "--format=%H%x1f%B%x1e", revision)
+	if err != nil {
+		return nil, err
+	}
+	var commits []Commit
+	for _, record := range strings.Split(out, "\x1e") {
+		hash, message, ok := strings.Cut(strings.TrimSpace(record), "\x1f")
+		if !ok {
+			continue
+		}
+		commits = append(commits, ParseCommit(hash, message, types, v))
+	}
+	return commits, nil
+}
+
+// CommitLevel returns the highest level of the commits along with the commits that imply it, which is LevelNone and no
+// commits when none of them implies a bump
+//
+// Example:
+// 		level, drivers := bump.CommitLevel(commits) // LevelMinor, [feat: add the export command]
+func CommitLevel(commits []Commit) (string, []Commit) {
+	level := LevelNone
+	for _, c := range commits {
+		if slices.Index(Levels, c.Level) < slices.Index(Levels, level) {
+			level = c.Level
+		}
+	}
+	if level == LevelNone {
+		return level, nil
+	}
+	var drivers []Commit
+	for _, c := range commits {
+		if c.Level == level {
+			drivers = append(drivers, c)
+		}
+	}
+	return level, drivers
+}
@@ -0,0 +1,323 @@
+package bump
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Constraint is a version range made of comparator sets joined by "||"; a Version satisfies the Constraint when it
+// matches every comparator of at least one set. Caret (^1.2), tilde (~1.2.3), wildcard (1.x, 1.2.*, *), hyphen
+// (1.2.3 - 2.3.4) and comparison (>=1.0.0 <2.0.0) ranges are supported using npm/Cargo semantics.
+type Constraint struct {
+	raw  string
+	sets [][]comparator
+}
+
+// comparator is a single operator and Version pair of a Constraint
+type comparator struct {
+	op         string   // one of =, !=, >, >=, <, <=
+	version    *Version // the version the operator compares against
+	preRelease bool     // true when the written range itself named a pre-release of this version
+}
+
+// partial is a possibly incomplete version of a range, where a missing or wildcard component is -1
+type partial struct {
+	major, minor, patch int
+	preRelease          []string
+}
+
+var (
+	// Constraint operator followed by a partial version (1, 1.2, 1.2.x, 1.2.3-rc.1)
+	reConstraint = regexp.MustCompile(`^(\^|~|>=|<=|>|<|=|!=)?\s*v?(\d+|[xX*])(?:\.(\d+|[xX*]))?(?:\.(\d+|[xX*]))?` +
+		`(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?$`)
+	// Constraint hyphen range (1.2.3 - 2.3.4)
+	reHyphenRange = regexp.MustCompile(`^(\S+)\s+-\s+(\S+)$`)
+)
+
+// ParseConstraint returns a new Constraint (or error) for the provided range
+//
+// Example:
+// 		c, err := bump.ParseConstraint(">=1.0.0 <2.0.0 || ^3.1")
+// 		v, _ := bump.Parse("v1.4.2")
+// 		ok := c.Satisfies(v) // true
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{raw: strings.TrimSpace(s)}
+	for _, set := range strings.Split(c.raw, "||") {
+		comparators, err := parseComparatorSet(strings.TrimSpace(set))
+		if err != nil {
+			return nil, fmt.Errorf("invalid range %q: %w", s, err)
+		}
+		c.sets = append(c.sets, comparators)
+	}
+	return c, nil
+}
+
+// String returns the range the Constraint was parsed from
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+// Satisfies reports whether v matches every comparator of at least one comparator set of the Constraint. A pre-release
+// version only matches a set that names a pre-release of the same major.minor.patch, like npm and Cargo.
+func (c *Constraint) Satisfies(v *Version) bool {
+	for _, set := range c.sets {
+		if satisfiesSet(set, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// satisfiesSet reports whether v matches every comparator of set
+func satisfiesSet(set []comparator, v *Version) bool {
+	for _, cmp := range set {
+		if !cmp.matches(v) {
+			return false
+		}
+	}
+	if len(v.identifiers()) == 0 {
+		return true
+	}
+	for _, cmp := range set {
+		o := cmp.version
+		if cmp.preRelease && o.Major == v.Major && o.Minor == v.Minor && o.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+// matches applies the operator of the comparator to v
+func (cmp comparator) matches(v *Version) bool {
+	c := v.Compare(cmp.version)
+	switch cmp.op {
+	case "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	}
+	return false
+}
+
+// parseComparatorSet desugars a whitespace (or comma) separated list of ranges into comparators
+func parseComparatorSet(set string) ([]comparator, error) {
+	if m := reHyphenRange.FindStringSubmatch(set); m != nil {
+		return parseHyphenRange(m[1], m[2])
+	}
+	var (
+		tokens      []string
+		comparators []comparator
+	)
+	for _, f := range strings.Fields(strings.ReplaceAll(set, ",", " ")) {
+		if n := len(tokens); n > 0 && isOperator(tokens[n-1]) {
+			tokens[n-1] += f
+			continue
+		}
+		tokens = append(tokens, f)
+	}
+	if len(tokens) == 0 {
+		tokens = []string{"*"}
+	}
+	for _, token := range tokens {
+		m := reConstraint.FindStringSubmatch(token)
+		if m == nil {
+			return nil, fmt.Errorf("unrecognized comparator %q", token)
+		}
+		p, err := parsePartial(m[2], m[3], m[4], m[5])
+		if err != nil {
+			return nil, err
+		}
+		desugared, err := desugar(m[1], p)
+		if err != nil {
+			return nil, err
+		}
+		comparators = append(comparators, desugared...)
+	}
+	return comparators, nil
+}
+
+// parseHyphenRange desugars "lower - upper" into an inclusive lower bound and an upper bound that includes every
+// version matching a partial upper version
+func parseHyphenRange(lower, upper string) ([]comparator, error) {
+	var comparators []comparator
+	for i, bound := range []string{lower, upper} {
+		m := reConstraint.FindStringSubmatch(bound)
+		if m == nil || len(m[1]) > 0 {
+			return nil, fmt.Errorf("unrecognized hyphen range bound %q", bound)
+		}
+		p, err := parsePartial(m[2], m[3], m[4], m[5])
+		if err != nil {
+			return nil, err
+		}
+		op := ">="
+		if i == 1 {
+			op = "<="
+		}
+		desugared, err := desugar(op, p)
+		if err != nil {
+			return nil, err
+		}
+		comparators = append(comparators, desugared...)
+	}
+	return comparators, nil
+}
+
+// desugar turns an operator and partial version into the equivalent list of primitive comparators
+func desugar(op string, p partial) ([]comparator, error) {
+	switch op {
+	case "", "=":
+		if p.major < 0 {
+			return []comparator{p.bound(">=", 0, 0, 0)}, nil
+		}
+		if p.minor < 0 {
+			return []comparator{p.bound(">=", p.major, 0, 0), p.upper(p.major+1, 0, 0)}, nil
+		}
+		if p.patch < 0 {
+			return []comparator{p.bound(">=", p.major, p.minor, 0), p.upper(p.major, p.minor+1, 0)}, nil
+		}
+		return []comparator{p.exact("=")}, nil
+	case "!=":
+		if p.patch < 0 {
+			return nil, fmt.Errorf("operator != requires a full version")
+		}
+		return []comparator{p.exact("!=")}, nil
+	case "^":
+		switch {
+		case p.major < 0:
+			return []comparator{p.bound(">=", 0, 0, 0)}, nil
+		case p.major > 0 || p.minor < 0:
+			return []comparator{p.lower(), p.upper(p.major+1, 0, 0)}, nil
+		case p.minor > 0 || p.patch < 0:
+			return []comparator{p.lower(), p.upper(0, p.minor+1, 0)}, nil
+		}
+		return []comparator{p.lower(), p.upper(0, 0, p.patch+1)}, nil
+	case "~":
+		switch {
+		case p.major < 0:
+			return []comparator{p.bound(">=", 0, 0, 0)}, nil
+		case p.minor < 0:
+			return []comparator{p.lower(), p.upper(p.major+1, 0, 0)}, nil
+		}
+		return []comparator{p.lower(), p.upper(p.major, p.minor+1, 0)}, nil
+	case ">":
+		switch {
+		case p.major < 0:
+			return []comparator{p.bound("<", 0, 0, 0)}, nil
+		case p.minor < 0:
+			return []comparator{p.bound(">=", p.major+1, 0, 0)}, nil
+		case p.patch < 0:
+			return []comparator{p.bound(">=", p.major, p.minor+1, 0)}, nil
+		}
+		return []comparator{p.exact(">")}, nil
+	case ">=":
+		if p.patch < 0 {
+			return []comparator{p.lower()}, nil
+		}
+		return []comparator{p.exact(">=")}, nil
+	case "<":
+		switch {
+		case p.major < 0:
+			return []comparator{p.bound("<", 0, 0, 0)}, nil
+		case p.patch < 0:
+			return []comparator{p.upper(p.major, max(p.minor, 0), 0)}, nil
+		}
+		return []comparator{p.exact("<")}, nil
+	case "<=":
+		switch {
+		case p.major < 0:
+			return []comparator{p.bound(">=", 0, 0, 0)}, nil
+		case p.minor < 0:
+			return []comparator{p.upper(p.major+1, 0, 0)}, nil
+		case p.patch < 0:
+			return []comparator{p.upper(p.major, p.minor+1, 0)}, nil
+		}
+		return []comparator{p.exact("<=")}, nil
+	}
+	return nil, fmt.Errorf("unsupported operator %q", op)
+}
+
+// parsePartial converts the captured components of reConstraint into a partial version
+func parsePartial(major, minor, patch, preRelease string) (partial, error) {
+	p := partial{major: -1, minor: -1, patch: -1}
+	for i, s := range []string{major, minor, patch} {
+		if len(s) == 0 || s == "*" || strings.EqualFold(s, "x") {
+			break
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return p, fmt.Errorf("invalid version component %q: %w", s, err)
+		}
+		switch i {
+		case 0:
+			p.major = n
+		case 1:
+			p.minor = n
+		case 2:
+			p.patch = n
+		}
+	}
+	if len(preRelease) > 0 {
+		if p.patch < 0 {
+			return p, fmt.Errorf("pre-release %q requires a full version", preRelease)
+		}
+		ids, err := splitIdentifiers(preRelease)
+		if err != nil {
+			return p, err
+		}
+		p.preRelease = ids
+	}
+	return p, nil
+}
+
+// isOperator reports whether token is a bare comparator operator that is separated from its version by whitespace
+func isOperator(token string) bool {
+	switch token {
+	case "^", "~", ">=", "<=", ">", "<", "=", "!=":
+		return true
+	}
+	return false
+}
+
+// exact returns a comparator for op against the full version of p including its pre-release
+func (p partial) exact(op string) comparator {
+	v := p.version(p.major, p.minor, p.patch)
+	v.PreRelease = p.preRelease
+	return comparator{op: op, version: v, preRelease: len(p.preRelease) > 0}
+}
+
+// lower returns the inclusive lower bound of p, filling missing components with zero
+func (p partial) lower() comparator {
+	if p.patch >= 0 {
+		return p.exact(">=")
+	}
+	return p.bound(">=", p.major, max(p.minor, 0), 0)
+}
+
+// upper returns an exclusive upper bound that also excludes the pre-releases of the bound version
+func (p partial) upper(major, minor, patch int) comparator {
+	v := p.version(major, minor, patch)
+	v.PreRelease = []string{"0"}
+	return comparator{op: "<", version: v}
+}
+
+// bound returns a comparator for op against the release version major.minor.patch
+func (p partial) bound(op string, major, minor, patch int) comparator {
+	return comparator{op: op, version: p.version(major, minor, patch)}
+}
+
+// version returns a new Version with the provided core components
+func (p partial) version(major, minor, patch int) *Version {
+	v := New()
+	v.Major, v.Minor, v.Patch = major, minor, patch
+	return v
+}
@@ -0,0 +1,161 @@
+package bump
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DockerKeys are the LABEL, ARG and ENV keys that are recognized as the version of a Dockerfile when no key is selected
+// with SetDockerKey
+var DockerKeys = []string{
+	"org.opencontainers.image.version",
+	"org.label-schema.version",
+	"version",
+	"VERSION",
+	"APP_VERSION",
+}
+
+var (
+	// Dockerfile LABEL, ARG or ENV instruction at the start of a line, in any case
+	reDockerInstruction = regexp.MustCompile(`(?m)^[ \t]*((?i)LABEL|ARG|ENV)[ \t]+`)
+	// Dockerfile key=value pair, where the value is double quoted, single quoted or bare
+	reDockerPair = regexp.MustCompile(`(?:^|\s)([A-Za-z0-9_.\-]+)=(?:"([^"]*)"|'([^']*)'|([^\s"'\\]+))`)
+	// Dockerfile legacy ENV key value form
+	reDockerLegacyEnv = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)[ \t]+(?:"([^"]*)"|([^\s"'\\]+))[ \t]*\r?$`)
+)
+
+// dockerValue is a key of a LABEL, ARG or ENV instruction in a Dockerfile and the location of its value
+type dockerValue struct {
+	instruction string
+	key         string
+	value       string
+	span        tomlSpan
+}
+
+// matches reports whether the dockerValue is selected by selector, which is KEY or INSTRUCTION:KEY (ARG:VERSION), or
+// is one of the DockerKeys when selector is empty
+func (d dockerValue) matches(selector string) bool {
+	if len(selector) == 0 {
+		for _, key := range DockerKeys {
+			if d.key == key {
+				return true
+			}
+		}
+		return false
+	}
+	instruction, key, ok := strings.Cut(selector, ":")
+	if !ok {
+		return d.key == selector
+	}
+	return strings.EqualFold(d.instruction, instruction) && d.key == key
+}
+
+// dockerValues returns every key of the LABEL, ARG and ENV instructions of the content in order, following backslash
+// line continuations so that multi-line LABEL instructions are included and skipping # comment lines
+func dockerValues(content []byte) []dockerValue {
+	var values []dockerValue
+	next := 0
+	for _, loc := range reDockerInstruction.FindAllSubmatchIndex(content, -1) {
+		if loc[0] < next {
+			continue // a continuation line of the previous instruction
+		}
+		instruction := strings.ToUpper(string(content[loc[2]:loc[3]]))
+		start, end := loc[1], dockerLineEnd(content, loc[1])
+		next = end
+		line := content[start:end]
+		if instruction == "ENV" && !bytes.Contains(bytes.SplitN(bytes.TrimSpace(line), []byte(" "), 2)[0], []byte("=")) {
+			if m := reDockerLegacyEnv.FindSubmatchIndex(line); m != nil {
+				values = append(values, dockerPair(instruction, content, start, m))
+			}
+			continue
+		}
+		for offset := start; offset < end; {
+			lineEnd := bytes.IndexByte(content[offset:end], '\n')
+			if lineEnd < 0 {
+				lineEnd = end - offset
+			}
+			if physical := content[offset : offset+lineEnd]; !dockerComment(physical) {
+				for _, m := range reDockerPair.FindAllSubmatchIndex(physical, -1) {
+					values = append(values, dockerPair(instruction, content, offset, m))
+				}
+			}
+			offset += lineEnd + 1
+		}
+	}
+	return values
+}
+
+// dockerComment reports whether the line is a # comment line of a Dockerfile
+func dockerComment(line []byte) bool {
+	return bytes.HasPrefix(bytes.TrimLeft(line, " \t"), []byte("#"))
+}
+
+// dockerPair converts the submatch indexes m of a key and its (optionally quoted) value in the line at offset into a
+// dockerValue
+func dockerPair(instruction string, content []byte, offset int, m []int) dockerValue {
+	d := dockerValue{instruction: instruction, key: string(content[offset+m[2] : offset+m[3]])}
+	for i := 4; i+1 < len(m); i += 2 {
+		if m[i] >= 0 {
+			d.span = tomlSpan{start: offset + m[i], end: offset + m[i+1]}
+			break
+		}
+	}
+	d.value = string(content[d.span.start:d.span.end])
+	return d
+}
+
+// dockerLineEnd returns the offset of the newline that ends the logical line starting at offset, skipping newlines that
+// follow a backslash line continuation and the # comment lines inside a continuation
+func dockerLineEnd(content []byte, offset int) int {
+	lineStart := offset
+	for i := offset; i < len(content); i++ {
+		if content[i] != '\n' {
+			continue
+		}
+		if lineStart > offset && dockerComment(content[lineStart:i]) {
+			lineStart = i + 1
+			continue
+		}
+		j := i - 1
+		for j >= lineStart && (content[j] == '\r' || content[j] == ' ' || content[j] == '\t') {
+			j--
+		}
+		if j < lineStart || content[j] != '\\' {
+			return i
+		}
+		lineStart = i + 1
+	}
+	return len(content)
+}
+
+// dockerVersion returns the authoritative dockerValue of the content for selector, which is the first matching key with
+// a literal value, and every matching key whose value is the same version (ignoring a "v" prefix) so that all stages of
+// a multi-stage build are updated together
+func dockerVersion(content []byte, selector string) (dockerValue, []dockerValue, error) {
+	var (
+		found   bool
+		version dockerValue
+		all     []dockerValue
+	)
+	values := dockerValues(content)
+	for _, d := range values {
+		if d.matches(selector) && len(d.value) > 0 && !strings.Contains(d.value, "$") {
+			version, found = d, true
+			break
+		}
+	}
+	if !found {
+		if len(selector) == 0 {
+			selector = strings.Join(DockerKeys, ", ")
+		}
+		return version, nil, fmt.Errorf("could not find a LABEL, ARG or ENV version (%s) in Dockerfile", selector)
+	}
+	for _, d := range values {
+		if (d.matches(selector) || d.matches("")) && strings.TrimPrefix(d.value, "v") == strings.TrimPrefix(version.value, "v") {
+			all = append(all, d)
+		}
+	}
+	return version, all, nil
+}
@@ -0,0 +1,126 @@
+package bump
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// git runs the local git binary with args inside of dir and returns the trimmed STDOUT, wrapping STDERR into the error
+func git(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// GitShortCommit returns the abbreviated commit hash of HEAD in the git repository at dir
+//
+// Example:
+// 		sha, err := bump.GitShortCommit(".")
+func GitShortCommit(dir string) (string, error) {
+	return git(dir, "rev-parse", "--short", "HEAD")
+}
+
+// GitLatestTag returns the tag with the highest version of the tags starting with prefix (such as v or mylib/v) that
+// are reachable from HEAD in the git repository at dir, along with its version, or an empty tag when there is none; the
+// version renders with a "v" prefix when prefix ends with one
+//
+// Example:
+// 		tag, v, err := bump.GitLatestTag(".", "v") // v1.2.3
+func GitLatestTag(dir, prefix string) (string, *Version, error) {
+	out, err := git(dir, "tag", "--merged", "HEAD", "--list", prefix+"*")
+	if err != nil {
+		return "", nil, err
+	}
+	var (
+		tag     string
+		highest *Version
+	)
+	for _, t := range strings.Fields(out) {
+		v, err := Parse(strings.TrimPrefix(t, prefix))
+		if err != nil {
+			continue
+		}
+		if highest == nil || v.Compare(highest) > 0 {
+			tag, highest = t, v
+		}
+	}
+	if highest != nil && strings.HasSuffix(prefix, "v") {
+		highest.SetNoPrefix(false)
+	}
+	return tag, highest, nil
+}
+
+// GitTagExists reports whether the tag exists in the git repository at dir
+func GitTagExists(dir, tag string) (bool, error) {
+	out, err := git(dir, "tag", "--list", tag)
+	if err != nil {
+		return false, err
+	}
+	return out == tag, nil
+}
+
+// GitTag creates the tag on HEAD of the git repository at dir, which is annotated with message, lightweight when message
+// is empty, or signed using the local gpg or ssh signing config of git when sign is set
+//
+// Example:
+// 		err := bump.GitTag(".", "v1.2.4", "Release v1.2.4", false)
+func GitTag(dir, tag, message string, sign bool) error {
+	args := []string{"tag"}
+	switch {
+	case sign && len(message) == 0:
+		return fmt.Errorf("cannot sign the lightweight tag %s, it needs a message", tag)
+	case sign:
+		args = append(args, "--sign", "--message", message)
+	case len(message) > 0:
+		args = append(args, "--annotate", "--message", message)
+	}
+	_, err := git(dir, append(args, tag)...)
+	return err
+}
+
+// GitStaged returns the absolute paths of the files staged in the index of the git repository at dir
+func GitStaged(dir string) ([]string, error) {
+	top, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	out, err := git(dir, "diff", "--cached", "--name-only")
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, name := range strings.Split(out, "\n") {
+		if len(name) > 0 {
+			paths = append(paths, filepath.Join(top, filepath.FromSlash(name)))
+		}
+	}
+	return paths, nil
+}
+
+// GitCommit stages the files and commits them with message in the git repository at dir, signing the commit using the
+// local gpg or ssh signing config of git when sign is set, and returns the abbreviated hash of the commit
+//
+// Example:
+// 		sha, err := bump.GitCommit(".", "chore(release): v1.2.3 → v1.2.4", []string{"VERSION"}, false)
+func GitCommit(dir, message string, files []string, sign bool) (string, error) {
+	if _, err := git(dir, append([]string{"add", "--"}, files...)...); err != nil {
+		return "", err
+	}
+	args := []string{"commit", "--quiet", "--message", message}
+	if sign {
+		args = append(args, "--gpg-sign")
+	}
+	if _, err := git(dir, args...); err != nil {
+		return "", err
+	}
+	return GitShortCommit(dir)
+}
@@ -0,0 +1,189 @@
+package bump
+
+import (
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// go.mod module directive, where group 2 is the (optionally quoted) module path
+	reGoModModule = regexp.MustCompile(`(?m)^(module[ \t]+"?)([^"\s]+)`)
+	// Major version suffix of a module path such as /v2
+	reGoMajorSuffix = regexp.MustCompile(`/v([2-9]|[1-9][0-9]+)$`)
+)
+
+// GoModuleMigration describes the rewrite of a Go module path to its next major version
+type GoModuleMigration struct {
+	Dir      string   `json:"dir"`      // directory of the go.mod
+	OldPath  string   `json:"old_path"` // module path before the migration, such as example.com/foo
+	NewPath  string   `json:"new_path"` // module path after the migration, such as example.com/foo/v2
+	OldMajor int      `json:"old_major"`
+	NewMajor int      `json:"new_major"`
+	Files    []string `json:"files"`             // go.mod and every .go file whose import paths change
+	Warning  string   `json:"warning,omitempty"` // set when the path and the latest tag disagree on the major version
+
+	changes map[string][]byte // new content of every file in Files
+	nested  []string          // module paths of the nested modules, whose packages keep their import paths
+}
+
+// GoModulePath returns the module path of the go.mod content and the major version its /vN suffix declares; a path
+// without a suffix is major 1 (or 0, which cannot be told apart from the path alone)
+func GoModulePath(content []byte) (string, int, error) {
+	m := reGoModModule.FindSubmatch(content)
+	if m == nil {
+		return "", 0, errors.New("could not find module directive in go.mod")
+	}
+	path := string(m[2])
+	if s := reGoMajorSuffix.FindStringSubmatch(path); s != nil {
+		major, err := strconv.Atoi(s[1])
+		return path, major, err
+	}
+	return path, 1, nil
+}
+
+// MigrateGoModule prepares the migration of the module of the go.mod at path to its next major version: the module
+// directive gains (or increments) its /vN suffix, and every import path of the module in the .go files of the module
+// tree is rewritten. Nested modules, vendor, testdata and hidden directories are skipped, and so are the imports of the
+// packages of nested modules. The latest released version of the module (such as the one GitLatestTag returns, or nil
+// when it is unknown) tells a v0 module apart from a v1 module; a v0 module is refused unless force is set, since it
+// becomes v1 by tagging v1.0.0 without changing its path. Nothing is written until Write is called.
+//
+// Example:
+// 		_, latest, err := bump.GitLatestTag(".", "v")
+// 		m, err := bump.MigrateGoModule("go.mod", latest, false)
+// 		err = m.Write() // example.com/foo → example.com/foo/v2
+func MigrateGoModule(path string, latest *Version, force bool) (*GoModuleMigration, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	oldPath, oldMajor, err := GoModulePath(content)
+	if err != nil {
+		return nil, err
+	}
+	m := &GoModuleMigration{
+		Dir:      filepath.Dir(path),
+		OldPath:  oldPath,
+		OldMajor: oldMajor,
+		NewMajor: oldMajor + 1,
+		changes:  make(map[string][]byte),
+	}
+	switch {
+	case latest == nil && oldMajor == 1:
+		m.Warning = fmt.Sprintf("module %s has no major version suffix and no released version, so it is treated as v1",
+			oldPath)
+	case latest != nil && latest.Major == 0 && oldMajor == 1:
+		if !force {
+			return nil, fmt.Errorf("module %s is at %s, tag v1.0.0 to release v1 without changing its path, "+
+				"or use -force to migrate it to /v2", oldPath, latest.String())
+		}
+		m.OldMajor = 0
+		m.Warning = fmt.Sprintf("module %s is at %s and skips v1 by moving to /v2", oldPath, latest.String())
+	case latest != nil && latest.Major != oldMajor:
+		m.Warning = fmt.Sprintf("module %s declares major version %d but its latest version is %s", oldPath, oldMajor,
+			latest.String())
+	}
+	if oldMajor == 1 {
+		m.NewPath = oldPath + "/v2"
+	} else {
+		m.NewPath = reGoMajorSuffix.ReplaceAllString(oldPath, "/v"+strconv.Itoa(m.NewMajor))
+	}
+	loc := reGoModModule.FindSubmatchIndex(content)
+	m.add(path, replaceSpan(content, tomlSpan{start: loc[4], end: loc[5]}, m.NewPath))
+
+	var sources []string
+	err = filepath.WalkDir(m.Dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p == m.Dir {
+				return nil
+			}
+			name := d.Name()
+			if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if nested, err := os.ReadFile(filepath.Join(p, FileGoMod)); err == nil {
+				if nestedPath, _, err := GoModulePath(nested); err == nil {
+					m.nested = append(m.nested, nestedPath)
+				}
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(p, ".go") {
+			sources = append(sources, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range sources {
+		if err := m.rewriteImports(p); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// rewriteImports records the new content of the .go file at path when any of its imports belong to the module
+func (m *GoModuleMigration) rewriteImports(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, content, parser.ImportsOnly)
+	if err != nil {
+		return fmt.Errorf("could not parse imports of %s: %w", path, err)
+	}
+	var changes []spanChange
+	for _, spec := range f.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return fmt.Errorf("invalid import %s in %s: %w", spec.Path.Value, path, err)
+		}
+		if !withinModule(importPath, m.OldPath) || slices.ContainsFunc(m.nested, func(nested string) bool {
+			return withinModule(importPath, nested)
+		}) {
+			continue
+		}
+		start := fset.Position(spec.Path.Pos()).Offset
+		changes = append(changes, spanChange{
+			span:  tomlSpan{start: start + 1, end: start + len(spec.Path.Value) - 1},
+			value: m.NewPath + strings.TrimPrefix(importPath, m.OldPath),
+		})
+	}
+	if len(changes) > 0 {
+		m.add(path, replaceSpans(content, changes...))
+	}
+	return nil
+}
+
+// withinModule reports whether importPath is the module path or one of its packages
+func withinModule(importPath, module string) bool {
+	return importPath == module || strings.HasPrefix(importPath, module+"/")
+}
+
+// add records the new content of the file at path
+func (m *GoModuleMigration) add(path string, content []byte) {
+	m.Files = append(m.Files, path)
+	m.changes[path] = content
+}
+
+// Write saves the go.mod and every .go file of the migration using WriteFiles, so that either every file is rewritten or
+// none is
+func (m *GoModuleMigration) Write() error {
+	return WriteFiles(m.changes)
+}
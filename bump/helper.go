@@ -1,7 +1,9 @@
 package bump
 
 import (
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -20,6 +22,96 @@ func compareInt(a, b int) int {
 	return 0
 }
 
+// fileKind returns the File<Kind> constant used by parse and Save for the base name of a path, matching file
+// extensions for the kinds that are not identified by an exact file name
+func fileKind(base string) string {
+	for _, f := range SupportedFiles {
+		if f == base {
+			return f
+		}
+	}
+	if strings.HasSuffix(base, ".py") {
+		return FilePython
+	}
+	if strings.HasSuffix(base, ".csproj") {
+		return FileCsproj
+	}
+	return base
+}
+
+// comparePreRelease implements SemVer 2.0.0 §11.3 and §11.4: a version without pre-release identifiers has higher
+// precedence, otherwise identifiers are compared left to right and a larger set wins when all preceding are equal
+func comparePreRelease(a, b []string) int {
+	if len(a) == 0 || len(b) == 0 {
+		return -compareInt(len(a), len(b))
+	}
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareIdentifier(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(a), len(b))
+}
+
+// compareIdentifier compares numeric identifiers numerically, ranks numeric below alphanumeric, orders two named
+// Channels by their position and falls back to ASCII ordering for every other pair of alphanumeric identifiers
+func compareIdentifier(a, b string) int {
+	aNumeric, bNumeric := isNumeric(a), isNumeric(b)
+	switch {
+	case aNumeric && bNumeric:
+		// numeric identifiers have no leading zeros, so the longer one is larger
+		if len(a) != len(b) {
+			return compareInt(len(a), len(b))
+		}
+		return strings.Compare(a, b)
+	case aNumeric:
+		return -1
+	case bNumeric:
+		return 1
+	}
+	if ai, bi := channelRank(a), channelRank(b); ai >= 0 && bi >= 0 {
+		return compareInt(ai, bi)
+	}
+	return strings.Compare(a, b)
+}
+
+// isNumeric reports whether s is a non-empty string of ASCII digits
+func isNumeric(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// channelRank returns the position of name within Channels or -1 when it is not a named channel
+func channelRank(name string) int {
+	for i, c := range Channels {
+		if c == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// currentGoVersion returns the version of the local Go toolchain using go env GOVERSION, without its "go" prefix
+func currentGoVersion() (string, error) {
+	out, err := exec.Command("go", "env", "GOVERSION").Output()
+	if err != nil {
+		return "", fmt.Errorf("go env GOVERSION: %w", err)
+	}
+	m := reGoVersion.FindStringSubmatch(strings.TrimSpace(string(out)))
+	if m == nil {
+		return "", fmt.Errorf("unsupported local Go version %q", strings.TrimSpace(string(out)))
+	}
+	return m[1], nil
+}
+
+// currentIgoVersion returns the contents of ~/go/version of the IGO "golang version manager"
 func currentIgoVersion() (string, error) {
 	lookingFor := filepath.Join(os.Getenv("HOME"), "go", "version")
 	err := checkfs.File(lookingFor, file.Options{Exists: true})
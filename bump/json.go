@@ -0,0 +1,92 @@
+package bump
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// jsonValue locates the string value of key inside the top-level object of a JSON document by walking its tokens, so
+// the value can be replaced with replaceSpan while preserving key order, indentation, escaping, line endings and the
+// final newline byte-for-byte. The returned span excludes the surrounding quotes.
+func jsonValue(content []byte, key string) (tomlSpan, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	tok, err := dec.Token()
+	if err != nil {
+		return tomlSpan{}, err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return tomlSpan{}, fmt.Errorf("expected a JSON object")
+	}
+	for dec.More() {
+		tok, err = dec.Token()
+		if err != nil {
+			return tomlSpan{}, err
+		}
+		name, _ := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return tomlSpan{}, err
+		}
+		if name != key {
+			continue
+		}
+		end := int(dec.InputOffset())
+		start := end - len(raw)
+		if len(raw) < 2 || raw[0] != '"' || !bytes.Equal(content[start:end], raw) {
+			return tomlSpan{}, fmt.Errorf("%s is not a string", key)
+		}
+		return tomlSpan{start: start + 1, end: end - 1}, nil
+	}
+	return tomlSpan{}, fmt.Errorf("%s key not found", key)
+}
+
+// jsonMembers locates the string values of every member of the object that is the value of key inside the top-level
+// object of a JSON document, such as the "dependencies" of a package.json, keyed by member name; members that are not
+// strings are skipped and a missing key returns no members
+func jsonMembers(content []byte, key string) (map[string]tomlSpan, error) {
+	members := make(map[string]tomlSpan)
+	dec := json.NewDecoder(bytes.NewReader(content))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+	for dec.More() {
+		if tok, err = dec.Token(); err != nil {
+			return nil, err
+		}
+		if name, _ := tok.(string); name != key {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if tok, err = dec.Token(); err != nil {
+			return nil, err
+		}
+		if d, ok := tok.(json.Delim); !ok || d != '{' {
+			return nil, fmt.Errorf("%s is not an object", key)
+		}
+		for dec.More() {
+			if tok, err = dec.Token(); err != nil {
+				return nil, err
+			}
+			name, _ := tok.(string)
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return nil, err
+			}
+			end := int(dec.InputOffset())
+			start := end - len(raw)
+			if len(raw) >= 2 && raw[0] == '"' && bytes.Equal(content[start:end], raw) {
+				members[name] = tomlSpan{start: start + 1, end: end - 1}
+			}
+		}
+		return members, nil
+	}
+	return members, nil
+}
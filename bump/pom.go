@@ -0,0 +1,174 @@
+package bump
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// reMavenProperty matches a CI-friendly Maven version such as ${revision}
+var reMavenProperty = regexp.MustCompile(`^\$\{([A-Za-z0-9_.-]+)}$`)
+
+// pom is the location of the version elements of a pom.xml, keyed by the slash-separated path of the element such as
+// project/version, project/parent/version or project/properties/revision
+type pom struct {
+	content []byte
+	spans   map[string]tomlSpan
+	modules []string
+}
+
+// parsePom walks the XML structure of a pom.xml and records the span of the text of every element that bump reads or
+// rewrites, so the values can be replaced while preserving the rest of the document byte-for-byte
+func parsePom(content []byte) (*pom, error) {
+	p := &pom{content: content, spans: make(map[string]tomlSpan)}
+	dec := xml.NewDecoder(bytes.NewReader(content))
+	dec.Strict = false
+	var (
+		path   []string
+		starts []int
+	)
+	for {
+		before := int(dec.InputOffset())
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid pom.xml: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			path = append(path, t.Name.Local)
+			starts = append(starts, int(dec.InputOffset()))
+		case xml.EndElement:
+			if len(path) == 0 {
+				return nil, errors.New("invalid pom.xml: unbalanced elements")
+			}
+			key := strings.Join(path, "/")
+			start := starts[len(starts)-1]
+			path, starts = path[:len(path)-1], starts[:len(starts)-1]
+			if !pomTracked(key) {
+				continue
+			}
+			span := trimSpan(content, tomlSpan{start: start, end: before})
+			if key == "project/modules/module" {
+				p.modules = append(p.modules, string(content[span.start:span.end]))
+				continue
+			}
+			if _, ok := p.spans[key]; !ok {
+				p.spans[key] = span
+			}
+		}
+	}
+	if len(p.spans) == 0 && len(p.modules) == 0 {
+		return nil, errors.New("invalid pom.xml: no <project> element")
+	}
+	return p, nil
+}
+
+// pomTracked reports whether the element at key is recorded by parsePom
+func pomTracked(key string) bool {
+	switch key {
+	case "project/version", "project/artifactId", "project/groupId", "project/modules/module",
+		"project/parent/version", "project/parent/artifactId", "project/parent/groupId":
+		return true
+	}
+	return strings.HasPrefix(key, "project/properties/") && strings.Count(key, "/") == 2
+}
+
+// value returns the trimmed text of the element at key
+func (p *pom) value(key string) (string, bool) {
+	span, ok := p.spans[key]
+	if !ok {
+		return "", false
+	}
+	return string(p.content[span.start:span.end]), true
+}
+
+// version locates the version of the pom.xml for target (PomProject or PomParent), following a ${property} reference
+// into <properties>; a project without its own <version> is refused rather than bumping the <parent> it inherits from
+func (p *pom) version(target string) (tomlSpan, error) {
+	key := "project/version"
+	if target == PomParent {
+		key = "project/parent/version"
+	}
+	span, ok := p.spans[key]
+	if !ok && target != PomParent {
+		if _, inherited := p.spans["project/parent/version"]; inherited {
+			return tomlSpan{}, errors.New("pom.xml has no <version> and inherits it from <parent>, use -pom-target=parent to bump the parent version")
+		}
+	}
+	if !ok {
+		return tomlSpan{}, fmt.Errorf("could not find <%s> in pom.xml", strings.TrimPrefix(key, "project/"))
+	}
+	if m := reMavenProperty.FindSubmatch(p.content[span.start:span.end]); m != nil {
+		property, ok := p.spans["project/properties/"+string(m[1])]
+		if !ok {
+			return tomlSpan{}, fmt.Errorf("could not find property %s in the <properties> of pom.xml", m[0])
+		}
+		return property, nil
+	}
+	return span, nil
+}
+
+// trimSpan shrinks span so that it excludes the leading and trailing whitespace of content
+func trimSpan(content []byte, span tomlSpan) tomlSpan {
+	for span.start < span.end && isSpace(content[span.start]) {
+		span.start++
+	}
+	for span.end > span.start && isSpace(content[span.end-1]) {
+		span.end--
+	}
+	return span
+}
+
+// isSpace reports whether b is XML whitespace
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
+
+// saveMavenModules writes the updated <parent><version> of every module of the reactor rooted at dir that references the
+// parent with groupId:artifactId at the old version, recursing into the modules of those modules
+func (v *Version) saveMavenModules(dir string, root *pom, old, newVersion string) error {
+	groupId, _ := root.value("project/groupId")
+	if len(groupId) == 0 {
+		groupId, _ = root.value("project/parent/groupId")
+	}
+	artifactId, _ := root.value("project/artifactId")
+	for _, module := range root.modules {
+		path := filepath.Join(dir, filepath.FromSlash(module), FileMavenPom)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("could not read module %s: %w", module, err)
+		}
+		child, err := parsePom(content)
+		if err != nil {
+			return fmt.Errorf("module %s: %w", module, err)
+		}
+		var changes []spanChange
+		parentGroupId, _ := child.value("project/parent/groupId")
+		parentArtifactId, _ := child.value("project/parent/artifactId")
+		if parentVersion, ok := child.value("project/parent/version"); ok && parentVersion == old &&
+			parentGroupId == groupId && parentArtifactId == artifactId {
+			changes = append(changes, spanChange{span: child.spans["project/parent/version"], value: newVersion})
+		}
+		if ownVersion, ok := child.value("project/version"); ok && ownVersion == old {
+			changes = append(changes, spanChange{span: child.spans["project/version"], value: newVersion})
+		}
+		if len(changes) > 0 {
+			if err := v.write(path, replaceSpans(content, changes...)); err != nil {
+				return err
+			}
+		}
+		if err := v.saveMavenModules(filepath.Dir(path), child, old, newVersion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
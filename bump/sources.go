@@ -0,0 +1,50 @@
+package bump
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// FindVersionFiles walks root for every file that holds the version of the project, which are the SupportedFiles
+// except go.mod (it holds the Go version) and the Python modules without a __version__ assignment. Hidden directories,
+// vendor, node_modules and testdata are skipped.
+//
+// Example:
+// 		paths, err := bump.FindVersionFiles(".") // [Chart.yaml VERSION web/package.json]
+func FindVersionFiles(root string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if p != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		kind := fileKind(d.Name())
+		if kind == FileGoMod || !slices.Contains(SupportedFiles, kind) {
+			return nil
+		}
+		if kind == FilePython {
+			content, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			if !rePythonVersion.Match(content) {
+				return nil
+			}
+		}
+		paths = append(paths, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
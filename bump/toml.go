@@ -0,0 +1,95 @@
+package bump
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// TOML (and INI) table header such as [package] or [workspace.package]
+	reTomlHeader = regexp.MustCompile(`^\s*\[\s*([^\[\]]+?)\s*\]\s*(?:[#;].*)?$`)
+	// TOML array of tables header such as [[bin]]
+	reTomlArrayHeader = regexp.MustCompile(`^\s*\[\[`)
+)
+
+// tomlSpan is the location of a value inside a TOML (or INI) document
+type tomlSpan struct {
+	start, end int // byte offsets of the value, excluding any surrounding quotes
+}
+
+// tomlValue locates the quoted string value of key inside the [section] table of a TOML document without decoding the
+// rest of it, so the value can be replaced while preserving comments, key order and formatting byte-for-byte
+func tomlValue(content []byte, section, key string) (tomlSpan, bool) {
+	re := regexp.MustCompile(`^(\s*` + regexp.QuoteMeta(key) + `\s*=\s*)(?:"([^"\\]*)"|'([^']*)')`)
+	return tableValue(content, section, func(line []byte) (int, int, bool) {
+		m := re.FindSubmatchIndex(line)
+		if m == nil {
+			return 0, 0, false
+		}
+		if m[4] >= 0 {
+			return m[4], m[5], true
+		}
+		return m[6], m[7], true
+	})
+}
+
+// tomlHasKey reports whether a line starting with key (including dotted keys such as version.workspace) exists inside
+// the [section] table of a TOML document
+func tomlHasKey(content []byte, section, key string) bool {
+	re := regexp.MustCompile(`^\s*` + regexp.QuoteMeta(key) + `\s*=`)
+	_, ok := tableValue(content, section, func(line []byte) (int, int, bool) {
+		return 0, 0, re.Match(line)
+	})
+	return ok
+}
+
+// tableValue walks the lines of content, tracking the current table header, and returns the span reported by match
+// for the first line inside section
+func tableValue(content []byte, section string, match func(line []byte) (int, int, bool)) (tomlSpan, bool) {
+	current, offset := "", 0
+	for _, line := range bytes.SplitAfter(content, []byte("\n")) {
+		lineStart := offset
+		offset += len(line)
+		if reTomlArrayHeader.Match(line) {
+			current = ""
+			continue
+		}
+		if m := reTomlHeader.FindSubmatch(line); m != nil {
+			current = strings.Join(strings.Fields(string(m[1])), "")
+			continue
+		}
+		if current != section {
+			continue
+		}
+		if start, end, ok := match(line); ok {
+			return tomlSpan{start: lineStart + start, end: lineStart + end}, true
+		}
+	}
+	return tomlSpan{}, false
+}
+
+// spanChange is a replacement value for the bytes of a tomlSpan
+type spanChange struct {
+	span  tomlSpan
+	value string
+}
+
+// replaceSpans returns a copy of content with every change applied, starting from the last span so that the offsets of
+// the earlier spans remain valid
+func replaceSpans(content []byte, changes ...spanChange) []byte {
+	sort.Slice(changes, func(i, j int) bool { return changes[i].span.start > changes[j].span.start })
+	for _, c := range changes {
+		content = replaceSpan(content, c.span, c.value)
+	}
+	return content
+}
+
+// replaceSpan returns a copy of content with the bytes of span replaced by value
+func replaceSpan(content []byte, span tomlSpan, value string) []byte {
+	out := make([]byte, 0, len(content)+len(value))
+	out = append(out, content[:span.start]...)
+	out = append(out, value...)
+	return append(out, content[span.end:]...)
+}
@@ -1,7 +1,10 @@
 package bump
 
 import (
+	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"sync"
 )
 
@@ -15,15 +18,26 @@ type Version struct {
 	useForm    string                 // control which format to use for rendering the version
 	isIgo      bool                   // determine whether or not igo is used
 	igoVersion string                 // stored igo version
+	pep440     bool                   // render the pre-release using the PEP 440 spelling (1.2.3rc1)
+	pep440Len  int                    // number of release segments of the parsed PEP 440 version (2 for 1.2b2)
+	appPolicy  string                 // one of AppVersionPolicies, controls the "appVersion" of a Chart.yaml
+	app        *Version               // the "appVersion" of a Chart.yaml bumped alongside under AppVersionDerive
+	pomTarget  string                 // one of PomTargets, controls which <version> of a pom.xml is used
+	dockerKey  string                 // KEY or INSTRUCTION:KEY selecting the authoritative version of a Dockerfile
+	goSource   string                 // one of GoSources or an explicit Go version, used when saving a go.mod
+	pending    map[string][]byte      // new content of every file written by Render, keyed by path
 
-	Major   int    `json:"major"`
-	Minor   int    `json:"minor"`
-	Patch   int    `json:"patch"`
-	Alpha   int    `json:"alpha"`
-	Beta    int    `json:"beta"`
-	RC      int    `json:"rc"`
-	Preview int    `json:"preview"`
-	Version string `json:"version"`
+	Major      int      `json:"major"`
+	Minor      int      `json:"minor"`
+	Patch      int      `json:"patch"`
+	Revision   int      `json:"revision,omitempty"`
+	Alpha      int      `json:"alpha"`
+	Beta       int      `json:"beta"`
+	RC         int      `json:"rc"`
+	Preview    int      `json:"preview"`
+	PreRelease []string `json:"prerelease,omitempty"`
+	Build      []string `json:"build,omitempty"`
+	Version    string   `json:"version"`
 }
 
 // LoadFile stores the []byte contents of the path into the raw property of the Version struct
@@ -47,6 +61,89 @@ func (v *Version) LoadFile(path string) error {
 	return nil
 }
 
+// SetAppVersionPolicy controls how the "appVersion" of a Chart.yaml is read and saved and must be called before Parse;
+// it is one of AppVersionIgnore (the default), AppVersionSync, AppVersionDerive or AppVersionOnly
+//
+// Example:
+// 		v := bump.New()
+// 		err := v.SetAppVersionPolicy(bump.AppVersionSync)
+// 		err = v.ParseFile("Chart.yaml")
+func (v *Version) SetAppVersionPolicy(policy string) error {
+	v.safety()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(policy) == 0 {
+		policy = AppVersionIgnore
+	}
+	if !slices.Contains(AppVersionPolicies, policy) {
+		return fmt.Errorf("invalid appVersion policy %q, expected one of %s", policy, strings.Join(AppVersionPolicies, ", "))
+	}
+	v.appPolicy = policy
+	return nil
+}
+
+// SetPomTarget controls which <version> of a pom.xml is read and saved and must be called before Parse; it is one of
+// PomProject (the default) or PomParent
+//
+// Example:
+// 		v := bump.New()
+// 		err := v.SetPomTarget(bump.PomParent)
+// 		err = v.ParseFile("pom.xml")
+func (v *Version) SetPomTarget(target string) error {
+	v.safety()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(target) == 0 {
+		target = PomProject
+	}
+	if !slices.Contains(PomTargets, target) {
+		return fmt.Errorf("invalid pom.xml target %q, expected one of %s", target, strings.Join(PomTargets, ", "))
+	}
+	v.pomTarget = target
+	return nil
+}
+
+// SetGoSource controls where the Go version that is saved into the "go" directive of a go.mod comes from; it is one of
+// GoSourceAuto (the default), GoSourceLocal, GoSourceToolchain, GoSourceIgo or an explicit version such as 1.24.5
+//
+// Example:
+// 		v := bump.New()
+// 		err := v.SetGoSource(bump.GoSourceLocal)
+// 		err = v.ParseFile("go.mod")
+// 		err = v.Save("go.mod")
+func (v *Version) SetGoSource(source string) error {
+	v.safety()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(source) == 0 {
+		source = GoSourceAuto
+	}
+	if !slices.Contains(GoSources, source) && !reGoVersion.MatchString(source) {
+		return fmt.Errorf("invalid Go version source %q, expected one of %s or a version such as 1.24.5", source,
+			strings.Join(GoSources, ", "))
+	}
+	v.goSource = source
+	return nil
+}
+
+// SetDockerKey selects the LABEL, ARG or ENV key that is the authoritative version of a Dockerfile and must be called
+// before Parse; it is KEY or INSTRUCTION:KEY, and an empty key uses the first of the DockerKeys found
+//
+// Example:
+// 		v := bump.New()
+// 		err := v.SetDockerKey("ARG:VERSION")
+// 		err = v.ParseFile("Dockerfile")
+func (v *Version) SetDockerKey(key string) error {
+	v.safety()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if instruction, _, ok := strings.Cut(key, ":"); ok && !slices.Contains([]string{"LABEL", "ARG", "ENV"}, strings.ToUpper(instruction)) {
+		return fmt.Errorf("invalid Dockerfile key %q, expected KEY or LABEL:KEY, ARG:KEY or ENV:KEY", key)
+	}
+	v.dockerKey = key
+	return nil
+}
+
 // safety is responsible for assuring that the mutex and map are not nil
 func (v *Version) safety() {
 	if v.mu == nil {
@@ -79,61 +176,94 @@ func (v *Version) NoPrefix() bool {
 	return v.noPrefix
 }
 
-// Compare is used to compare different Version structs for comparison
-func (v *Version) Compare(o *Version) int {
+// prefixForms pairs the Forms that render a "v" prefix with their equivalent Form without one
+var prefixForms = map[string]string{FormA: FormG, FormJ: FormH, FormL: FormK}
+
+// SetNoPrefix controls whether or not the Version prepends a "v" prefix on the output Format, switching the Form it was
+// parsed with to its equivalent with (or without) the prefix
+//
+// Example:
+// 		v, _ := bump.Parse("1.2.3")
+// 		v.SetNoPrefix(false) // v1.2.3
+func (v *Version) SetNoPrefix(noPrefix bool) {
 	v.safety()
-	if v.Major != o.Major {
-		if v.Major > o.Major {
-			return 1
-		}
-		return -1
-	}
-	if v.Minor != o.Minor {
-		if v.Minor > o.Minor {
-			return 1
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.setNoPrefix(noPrefix)
+}
+
+// setNoPrefix is the internal, lock-free implementation of SetNoPrefix
+func (v *Version) setNoPrefix(noPrefix bool) {
+	v.noPrefix = noPrefix
+	for prefixed, plain := range prefixForms {
+		switch {
+		case noPrefix && v.useForm == prefixed:
+			v.useForm = plain
+			return
+		case !noPrefix && v.useForm == plain:
+			v.useForm = prefixed
+			return
 		}
-		return -1
 	}
-	if v.Patch != o.Patch {
-		if v.Patch > o.Patch {
-			return 1
-		}
-		return -1
+	if noPrefix && strings.HasPrefix(v.useForm, "v") {
+		v.useForm = ""
 	}
+}
 
-	var (
-		vIsPre     = v.Preview > 0 || v.RC > 0 || v.Beta > 0 || v.Alpha > 0
-		otherIsPre = o.Preview > 0 || o.RC > 0 || o.Beta > 0 || o.Alpha > 0
-
-		check1 = vIsPre == false && otherIsPre == true
-		check2 = vIsPre == true && otherIsPre == false
-		check3 = vIsPre == false && otherIsPre == false
-	)
-
-	if check1 {
-		return 1
-	}
-	if check2 {
-		return -1
-	}
-	if check3 {
-		return 0
+// Assign sets the version of v to the version of o while keeping the "v" prefix, path and file settings of v, so that
+// a version computed from one file can be saved into another
+//
+// Example:
+// 		a, b := bump.New(), bump.New()
+// 		err := a.ParseFile("VERSION")      // v1.2.3
+// 		err = b.ParseFile("package.json") // 1.2.3
+// 		a.BumpMinor()
+// 		b.Assign(a) // 1.3.0
+func (v *Version) Assign(o *Version) {
+	o.safety()
+	o.mu.RLock()
+	n := Version{
+		Major: o.Major, Minor: o.Minor, Patch: o.Patch, Revision: o.Revision,
+		Alpha: o.Alpha, Beta: o.Beta, RC: o.RC, Preview: o.Preview,
+		PreRelease: slices.Clone(o.PreRelease), Build: slices.Clone(o.Build),
+		useForm: o.useForm,
 	}
+	o.mu.RUnlock()
+	v.safety()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.Major, v.Minor, v.Patch, v.Revision = n.Major, n.Minor, n.Patch, n.Revision
+	v.Alpha, v.Beta, v.RC, v.Preview = n.Alpha, n.Beta, n.RC, n.Preview
+	v.PreRelease, v.Build = n.PreRelease, n.Build
+	v.useForm = n.useForm
+	v.setNoPrefix(v.noPrefix)
+}
 
-	// Both are pre-releases, compare them
-	if v.Preview != o.Preview {
-		return compareInt(v.Preview, o.Preview)
+// Path returns the path of the file the Version was loaded from or last saved to
+func (v *Version) Path() string {
+	v.safety()
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.path
+}
+
+// Compare is used to compare different Version structs for comparison using the SemVer 2.0.0 precedence rules,
+// ignoring Build metadata and ranking the named pre-release Channels by their configured order
+func (v *Version) Compare(o *Version) int {
+	v.safety()
+	if v.Major != o.Major {
+		return compareInt(v.Major, o.Major)
 	}
-	if v.RC != o.RC {
-		return compareInt(v.RC, o.RC)
+	if v.Minor != o.Minor {
+		return compareInt(v.Minor, o.Minor)
 	}
-	if v.Beta != o.Beta {
-		return compareInt(v.Beta, o.Beta)
+	if v.Patch != o.Patch {
+		return compareInt(v.Patch, o.Patch)
 	}
-	if v.Alpha != o.Alpha {
-		return compareInt(v.Alpha, o.Alpha)
+	if v.Revision != o.Revision {
+		return compareInt(v.Revision, o.Revision)
 	}
-	return 0
+	return comparePreRelease(v.identifiers(), o.identifiers())
 }
 
 // SetRaw allows you to overwrite the contents of the `-in` file passed into the package
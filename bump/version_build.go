@@ -0,0 +1,36 @@
+package bump
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetBuild replaces the build metadata of the Version with the dot-separated identifiers provided; build metadata is
+// rendered after a "+" and is ignored by Compare
+//
+// Example:
+// 		v, _ := bump.Parse("v1.4.0")
+// 		err := v.SetBuild("20261017.abc1234") // v1.4.0+20261017.abc1234
+func (v *Version) SetBuild(build string) error {
+	v.safety()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	build = strings.TrimPrefix(build, "+")
+	if v.app != nil {
+		if err := v.app.SetBuild(build); err != nil {
+			return err
+		}
+	}
+	if len(build) == 0 {
+		v.Build = nil
+		return nil
+	}
+	ids := strings.Split(build, ".")
+	for _, id := range ids {
+		if !reBuildIdentifier.MatchString(id) {
+			return fmt.Errorf("invalid build metadata identifier %q in %q", id, build)
+		}
+	}
+	v.Build = ids
+	return nil
+}
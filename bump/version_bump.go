@@ -1,75 +1,251 @@
 package bump
 
-import "strings"
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
 
 // BumpMajor is responsible for increasing the Major field in the Version struct
 func (v *Version) BumpMajor() {
 	v.safety()
+	v.alongside((*Version).BumpMajor)
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	v.Major++
 	v.Minor, v.Patch, v.RC, v.Alpha, v.Beta, v.Preview = 0, 0, 0, 0, 0, 0
+	v.Revision = 0
+	v.PreRelease, v.Build = nil, nil
+	v.releaseForm()
 }
 
 // BumpMinor is responsible for increasing the Minor field in the Version struct
 func (v *Version) BumpMinor() {
 	v.safety()
+	v.alongside((*Version).BumpMinor)
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	v.Minor++
 	v.Patch, v.RC, v.Alpha, v.Beta, v.Preview = 0, 0, 0, 0, 0
+	v.Revision = 0
+	v.PreRelease, v.Build = nil, nil
+	v.releaseForm()
 }
 
 // BumpPatch is responsible for increasing the Patch field in the Version struct
 func (v *Version) BumpPatch() {
 	v.safety()
+	v.alongside((*Version).BumpPatch)
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	v.Patch++
 	v.RC, v.Alpha, v.Beta, v.Preview = 0, 0, 0, 0
-	if !strings.EqualFold(v.useForm, FormG) {
+	v.Revision = 0
+	v.PreRelease, v.Build = nil, nil
+	if len(v.useForm) == 0 && v.noPrefix {
+		v.useForm = FormG
+	} else if !strings.EqualFold(v.useForm, FormG) && v.useForm != FormK && v.useForm != FormL {
 		v.useForm = FormA
 	}
 }
 
+// BumpRevision is responsible for increasing the Revision field of a four part Version in the Version struct
+func (v *Version) BumpRevision() {
+	v.safety()
+	v.alongside((*Version).BumpRevision)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.Revision++
+	v.RC, v.Alpha, v.Beta, v.Preview = 0, 0, 0, 0
+	v.PreRelease, v.Build = nil, nil
+	if v.noPrefix {
+		v.useForm = FormK
+	} else {
+		v.useForm = FormL
+	}
+}
+
+// Release is responsible for dropping the pre-release (such as -SNAPSHOT or -rc.2) and build metadata from the Version
+// in the Version struct so it becomes the final release of the same MAJOR.MINOR.PATCH
+func (v *Version) Release() {
+	v.safety()
+	v.alongside((*Version).Release)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.release()
+}
+
+// release is the internal, lock-free implementation of Release
+func (v *Version) release() {
+	v.RC, v.Alpha, v.Beta, v.Preview = 0, 0, 0, 0
+	v.PreRelease, v.Build = nil, nil
+	v.releaseForm()
+}
+
+// Channel returns the pre-release channel of the Version, such as rc for v1.2.3-rc.2, or "" for a final release
+func (v *Version) Channel() string {
+	v.safety()
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.channel()
+}
+
+// channel is the internal, lock-free implementation of Channel
+func (v *Version) channel() string {
+	if ids := v.identifiers(); len(ids) > 0 {
+		return ids[0]
+	}
+	return ""
+}
+
+// Promote advances the Version to the next channel of the Ladder with its counter reset to 1, and from the last channel
+// of the Ladder to its final release
+//
+// Example:
+// 		v, _ := bump.Parse("v1.2.3-beta.4")
+// 		err := v.Promote() // v1.2.3-rc.1
+// 		err = v.Promote()  // v1.2.3
+func (v *Version) Promote() error {
+	v.safety()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.app != nil {
+		if err := v.app.Promote(); err != nil {
+			return err
+		}
+	}
+	current := v.channel()
+	if len(current) == 0 {
+		return fmt.Errorf("%s is already a final release", v.format(true))
+	}
+	i := slices.Index(Ladder, current)
+	switch {
+	case i < 0:
+		return fmt.Errorf("%s is not one of the channels %s", current, strings.Join(Ladder, " → "))
+	case i == len(Ladder)-1:
+		v.release()
+	default:
+		v.setChannel(Ladder[i+1])
+	}
+	return nil
+}
+
+// SetChannel moves the Version to the channel of the Ladder with its counter reset to 1, and refuses to move it back to
+// an earlier channel of the Ladder or a final release to any channel, since both would lower the version; moving to its
+// current channel keeps the Version as it is
+//
+// Example:
+// 		v, _ := bump.Parse("v1.2.3-alpha.4")
+// 		err := v.SetChannel("rc")    // v1.2.3-rc.1
+// 		err = v.SetChannel("alpha") // error
+func (v *Version) SetChannel(name string) error {
+	v.safety()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.app != nil {
+		if err := v.app.SetChannel(name); err != nil {
+			return err
+		}
+	}
+	to := slices.Index(Ladder, name)
+	if to < 0 {
+		return fmt.Errorf("%s is not one of the channels %s", name, strings.Join(Ladder, " → "))
+	}
+	current := v.channel()
+	switch {
+	case len(current) == 0:
+		return fmt.Errorf("cannot move the final release %s back to %s", v.format(true), name)
+	case current == name:
+		return nil
+	case slices.Index(Ladder, current) > to:
+		return fmt.Errorf("cannot move %s back from %s to %s", v.format(true), current, name)
+	}
+	v.setChannel(name)
+	return nil
+}
+
+// setChannel replaces the pre-release of the Version with the first of the channel name
+func (v *Version) setChannel(name string) {
+	v.setPreReleaseForm(v.applyPreRelease([]string{name, "1"}))
+	v.Build = nil
+}
+
+// setPreReleaseForm sets useForm to the pre-release Form, which always renders a "v", unless the Version has no prefix,
+// where the empty Form renders the pre-release identifiers without one
+func (v *Version) setPreReleaseForm(form string) {
+	if v.noPrefix {
+		form = ""
+	}
+	v.useForm = form
+}
+
 // BumpRC is responsible for increasing the RC field in the Version struct
 func (v *Version) BumpRC() {
 	v.safety()
+	v.alongside((*Version).BumpRC)
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	v.RC++
 	v.Alpha, v.Beta, v.Preview = 0, 0, 0
-	v.useForm = FormD
+	v.setPreReleaseForm(FormD)
+	v.PreRelease, v.Build = v.identifiers(), nil
 }
 
 // BumpAlpha is responsible for increasing the Alpha field in the Version struct
 func (v *Version) BumpAlpha() {
 	v.safety()
+	v.alongside((*Version).BumpAlpha)
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	v.Alpha++
-	if v.useForm == FormD {
-		v.useForm = FormE
+	v.RC, v.Preview = 0, 0
+	if v.Beta > 0 {
+		v.setPreReleaseForm(FormE)
 	} else {
-		v.useForm = FormB
+		v.setPreReleaseForm(FormB)
 	}
+	v.PreRelease, v.Build = v.identifiers(), nil
 }
 
 // BumpBeta is responsible for increasing the Beta field in the Version struct
 func (v *Version) BumpBeta() {
 	v.safety()
+	v.alongside((*Version).BumpBeta)
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	v.Beta++
-	v.useForm = FormB
+	v.RC, v.Preview = 0, 0
+	if v.Alpha > 0 {
+		v.setPreReleaseForm(FormE)
+	} else {
+		v.setPreReleaseForm(FormC)
+	}
+	v.PreRelease, v.Build = v.identifiers(), nil
 }
 
 // BumpPreview is responsible for increasing the Preview field in the Version struct
 func (v *Version) BumpPreview() {
 	v.safety()
+	v.alongside((*Version).BumpPreview)
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	v.Preview++
-	v.Patch, v.Alpha, v.Beta, v.RC = 0, 0, 0, 0
-	v.useForm = FormF
+	v.Alpha, v.Beta, v.RC = 0, 0, 0
+	v.setPreReleaseForm(FormF)
+	v.PreRelease, v.Build = v.identifiers(), nil
+}
+
+// alongside applies bump to the "appVersion" of a Chart.yaml that was parsed using AppVersionDerive
+func (v *Version) alongside(bump func(*Version)) {
+	if v.app != nil {
+		bump(v.app)
+	}
+}
+
+// releaseForm drops a pre-release Form from useForm once the pre-release fields have been reset by a core bump
+func (v *Version) releaseForm() {
+	switch v.useForm {
+	case FormB, FormC, FormD, FormE, FormF:
+		v.useForm = FormA
+	}
 }
@@ -16,6 +16,19 @@ func (v *Version) Format(withPrefix bool) string {
 // format is the internal, lock-free implementation for creating a version string.
 func (v *Version) format(withPrefix bool) string {
 	v.safety()
+	if len(v.Build) == 0 {
+		return v.formatVersion(withPrefix)
+	}
+	return v.formatVersion(withPrefix) + "+" + strings.Join(v.Build, ".")
+}
+
+// formatVersion renders the version string without the build metadata using the useForm of the Version.
+func (v *Version) formatVersion(withPrefix bool) string {
+	if v.pep440 {
+		if s, ok := v.formatPep440(); ok {
+			return s
+		}
+	}
 	baseFormat := "%d.%d.%d"
 	if withPrefix && !v.noPrefix {
 		baseFormat = "v%d.%d.%d"
@@ -37,7 +50,7 @@ func (v *Version) format(withPrefix bool) string {
 		case FormD:
 			return fmt.Sprintf(FormD, v.Major, v.Minor, v.Patch, v.RC)
 		case FormE:
-			return fmt.Sprintf(FormE, v.Major, v.Minor, v.Patch, v.Alpha, v.Beta)
+			return fmt.Sprintf(FormE, v.Major, v.Minor, v.Patch, v.Beta, v.Alpha)
 		case FormF:
 			return fmt.Sprintf(FormF, v.Major, v.Minor, v.Patch, v.Preview)
 		case FormG:
@@ -51,22 +64,62 @@ func (v *Version) format(withPrefix bool) string {
 				return fmt.Sprintf(FormJ, v.Major, v.Minor)
 			}
 			return fmt.Sprintf(FormH, v.Major, v.Minor) // FormH not FormJ
+		case FormK:
+			return fmt.Sprintf(FormK, v.Major, v.Minor, v.Patch, v.Revision)
+		case FormL:
+			if withPrefix {
+				return fmt.Sprintf(FormL, v.Major, v.Minor, v.Patch, v.Revision)
+			}
+			return fmt.Sprintf(FormK, v.Major, v.Minor, v.Patch, v.Revision) // FormK not FormL
 		default:
 		}
 	}
 
 	base := fmt.Sprintf(baseFormat, v.Major, v.Minor, v.Patch)
 	var preRelease string
-	if v.Preview > 0 {
-		preRelease = fmt.Sprintf("-preview.%d", v.Preview)
-	} else if v.RC > 0 {
-		preRelease = fmt.Sprintf("-rc.%d", v.RC)
-	} else if v.Beta > 0 && v.Alpha > 0 {
-		preRelease = fmt.Sprintf("-beta.%d-alpha.%d", v.Beta, v.Alpha)
-	} else if v.Beta > 0 {
-		preRelease = fmt.Sprintf("-beta.%d", v.Beta)
-	} else if v.Alpha > 0 {
-		preRelease = fmt.Sprintf("-alpha.%d", v.Alpha)
+	if ids := v.identifiers(); len(ids) > 0 {
+		preRelease = "-" + strings.Join(ids, ".")
 	}
 	return fmt.Sprintf("%s%s", base, preRelease)
 }
+
+// formatCore returns the numeric major.minor.patch of the version, including the revision for four part versions.
+func (v *Version) formatCore() string {
+	if v.useForm == FormK || v.useForm == FormL {
+		return fmt.Sprintf(FormK, v.Major, v.Minor, v.Patch, v.Revision)
+	}
+	return fmt.Sprintf(FormG, v.Major, v.Minor, v.Patch)
+}
+
+// pep440Segments maps the pre-release identifiers of bump onto their normalized PEP 440 segment spelling
+var pep440Segments = map[string]string{
+	"alpha":   "a",
+	"beta":    "b",
+	"rc":      "rc",
+	"preview": "rc",
+	"dev":     ".dev",
+}
+
+// formatPep440 renders the version in normalized PEP 440 spelling (1.2.3, 1.2.3a1, 1.2.3rc2, 1.2.3.dev4), keeping the
+// two release segments of a parsed 1.2b2 while the patch is zero, reporting false when the pre-release identifiers have
+// no PEP 440 equivalent
+func (v *Version) formatPep440() (string, bool) {
+	var out strings.Builder
+	if v.pep440Len == 2 && v.Patch == 0 {
+		out.WriteString(fmt.Sprintf("%d.%d", v.Major, v.Minor))
+	} else {
+		out.WriteString(fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch))
+	}
+	ids := v.identifiers()
+	if len(ids)%2 != 0 {
+		return "", false
+	}
+	for i := 0; i < len(ids); i += 2 {
+		segment, ok := pep440Segments[ids[i]]
+		if !ok || !isNumeric(ids[i+1]) {
+			return "", false
+		}
+		out.WriteString(segment + ids[i+1])
+	}
+	return out.String(), true
+}
@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
-	"gopkg.in/yaml.v3"
+	"fmt"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 )
 
 // ParseFile uses LoadFile on the path to return Parse()
@@ -31,7 +34,7 @@ func (v *Version) Parse() error {
 // v.parse<Kind>() func with the provided []byte content, otherwise, we'll just v.scan() the content
 func (v *Version) parse(base string, content []byte) error {
 	var err error
-	switch base {
+	switch fileKind(base) {
 	case FileVersion:
 		err = v.parseVersion(content)
 	case FilePackageJson:
@@ -44,6 +47,22 @@ func (v *Version) parse(base string, content []byte) error {
 		err = v.parseGoMod(content)
 	case FileMavenPom:
 		err = v.parseMavenPom(content)
+	case FileCargoToml:
+		err = v.parseCargoToml(content)
+	case FilePyProject:
+		err = v.parsePyProject(content)
+	case FileSetupCfg:
+		err = v.parseSetupCfg(content)
+	case FilePython:
+		err = v.parsePython(content)
+	case FileGradleProperties:
+		err = v.parseGradleProperties(content)
+	case FileBuildGradle, FileBuildGradleKts:
+		err = v.parseBuildGradle(content)
+	case FileCsproj, FileBuildProps:
+		err = v.parseMSBuild(content)
+	case FileAssemblyInfo:
+		err = v.parseAssemblyInfo(content)
 	default:
 		err = v.scan(content)
 	}
@@ -74,32 +93,39 @@ func (v *Version) parsePackageJson(content []byte) error {
 	return v.scan([]byte(vs))
 }
 
-// parseHelmChart (yaml) unmarshal's the provided []bytes into a map[string]interface{}, extracts the "version" key and
-// returns v.scan() on the []byte contents of the value of the "version" key in the map
+// parseHelmChart (yaml) uses yamlScalar to locate the "version" key in the yaml.v3 node tree of the provided []bytes and
+// returns v.scan() on the []byte contents of its value, or of the "appVersion" key when using AppVersionOnly
 func (v *Version) parseHelmChart(content []byte) error {
-	m := make(map[string]interface{})
-	if err := yaml.Unmarshal(content, &m); err != nil {
-		return err
+	key := "version"
+	if v.appPolicy == AppVersionOnly {
+		key = "appVersion"
 	}
-	v.parsed = m
-	ver, ok := m["version"]
-	if !ok {
-		return errors.New("version key not found in Chart.yaml")
+	_, node, err := yamlScalar(content, key)
+	if err != nil {
+		return fmt.Errorf("invalid Chart.yaml: %w", err)
 	}
-	vs, ok := ver.(string)
-	if !ok {
-		return errors.New("version is not a string in Chart.yaml")
+	v.app = nil
+	if v.appPolicy == AppVersionDerive {
+		_, appNode, err := yamlScalar(content, "appVersion")
+		if err != nil {
+			return fmt.Errorf("invalid Chart.yaml: %w", err)
+		}
+		v.app = New()
+		if err := v.app.scan([]byte(appNode.Value)); err != nil {
+			return fmt.Errorf("invalid Chart.yaml appVersion: %w", err)
+		}
 	}
-	return v.scan([]byte(vs))
+	return v.scan([]byte(node.Value))
 }
 
-// parseDockerfile (text) uses regex reDockerfileVersion to FindSubmatch on the content and return v.scan(matches[2]) of the result
+// parseDockerfile (text) uses dockerVersion to find the LABEL, ARG or ENV version selected by the Docker key and return
+// v.scan() of its value
 func (v *Version) parseDockerfile(content []byte) error {
-	matches := reDockerfileVersion.FindSubmatch(content)
-	if len(matches) < 3 {
-		return errors.New("could not find version LABEL in Dockerfile")
+	version, _, err := dockerVersion(content, v.dockerKey)
+	if err != nil {
+		return err
 	}
-	return v.scan(matches[2])
+	return v.scan([]byte(version.value))
 }
 
 // parseGoMod (text) uses regex reGoModVersion to FindSubmatch on the content and return v.scan(matches[2]) of the result
@@ -115,21 +141,204 @@ func (v *Version) parseGoMod(content []byte) error {
 	return v.scan(matches[2])
 }
 
-// parseMavenPom (text) uses regex reMavenVersion to FindSubmatch on the content and return v.scan(matches[2]) of the result
+// parseMavenPom (xml) uses parsePom to walk the elements of the content and returns v.scan() of the <version> of the
+// project, or of its <parent> when using PomParent, resolving ${property} references from <properties>
 func (v *Version) parseMavenPom(content []byte) error {
-	matches := reMavenVersion.FindSubmatch(content)
-	if len(matches) < 3 {
-		return errors.New("could not find <version> tag inside <project> in pom.xml")
+	p, err := parsePom(content)
+	if err != nil {
+		return err
 	}
-	return v.scan(matches[2])
+	span, err := p.version(v.pomTarget)
+	if err != nil {
+		return err
+	}
+	return v.scan(content[span.start:span.end])
+}
+
+// parseCargoToml (text) uses cargoVersion to find the version of the [package] table, or the [workspace.package] table
+// when the package inherits it from a workspace, and returns v.scan() of the result
+func (v *Version) parseCargoToml(content []byte) error {
+	span, err := cargoVersion(content)
+	if err != nil {
+		return err
+	}
+	return v.scan(content[span.start:span.end])
+}
+
+// cargoVersion locates the version string of a Cargo.toml in [package], falling back to [workspace.package] for virtual
+// workspaces and packages that use version.workspace = true
+func cargoVersion(content []byte) (tomlSpan, error) {
+	if span, ok := tomlValue(content, "package", "version"); ok {
+		return span, nil
+	}
+	if span, ok := tomlValue(content, "workspace.package", "version"); ok {
+		return span, nil
+	}
+	if tomlHasKey(content, "package", "version") {
+		return tomlSpan{}, errors.New("package version in Cargo.toml is inherited but no [workspace.package] version was found")
+	}
+	return tomlSpan{}, errors.New("could not find version in [package] or [workspace.package] of Cargo.toml")
+}
+
+// parsePyProject (text) uses pyProjectVersion to find the version of the [project] table, or the [tool.poetry] table,
+// and returns v.scan() of the result so PEP 440 spellings such as 1.2.3rc1 are understood
+func (v *Version) parsePyProject(content []byte) error {
+	span, err := pyProjectVersion(content)
+	if err != nil {
+		return err
+	}
+	return v.scan(content[span.start:span.end])
+}
+
+// pyProjectVersion locates the version string of a pyproject.toml in [project] (PEP 621) or [tool.poetry]
+func pyProjectVersion(content []byte) (tomlSpan, error) {
+	if span, ok := tomlValue(content, "project", "version"); ok {
+		return span, nil
+	}
+	if span, ok := tomlValue(content, "tool.poetry", "version"); ok {
+		return span, nil
+	}
+	if tomlHasKey(content, "project", "dynamic") {
+		return tomlSpan{}, errors.New("version in pyproject.toml is dynamic, use -in on the file that defines it")
+	}
+	return tomlSpan{}, errors.New("could not find version in [project] or [tool.poetry] of pyproject.toml")
+}
+
+// parseSetupCfg (text) uses setupCfgVersion to find the version of the [metadata] section and returns v.scan() of it
+func (v *Version) parseSetupCfg(content []byte) error {
+	span, err := setupCfgVersion(content)
+	if err != nil {
+		return err
+	}
+	return v.scan(content[span.start:span.end])
+}
+
+// setupCfgVersion locates the version value of the [metadata] section of a setup.cfg
+func setupCfgVersion(content []byte) (tomlSpan, error) {
+	span, ok := tableValue(content, "metadata", func(line []byte) (int, int, bool) {
+		m := reSetupCfgVersion.FindSubmatchIndex(line)
+		if m == nil {
+			return 0, 0, false
+		}
+		return m[4], m[5], true
+	})
+	if !ok {
+		return tomlSpan{}, errors.New("could not find version in [metadata] of setup.cfg")
+	}
+	if value := string(content[span.start:span.end]); strings.HasPrefix(value, "attr:") || strings.HasPrefix(value, "file:") {
+		return tomlSpan{}, errors.New("version in setup.cfg is read from " + value + ", use -in on that file instead")
+	}
+	return span, nil
 }
 
-// parseIgo reads ~/go/version from the IGO "golang version manager" and uses that version for
-// a -fix on an -in go.mod file that needs to be corrected
-func (v *Version) parseIgo() error {
-	igoVersion, err := currentIgoVersion()
+// parsePython (text) uses pythonVersion to find the __version__ assignment of a Python module and returns v.scan() of it
+func (v *Version) parsePython(content []byte) error {
+	span, err := pythonVersion(content)
 	if err != nil {
 		return err
 	}
-	return v.parseVersion([]byte(igoVersion))
+	return v.scan(content[span.start:span.end])
+}
+
+// pythonVersion locates the quoted value of the first __version__ assignment of a Python module
+func pythonVersion(content []byte) (tomlSpan, error) {
+	m := rePythonVersion.FindSubmatchIndex(content)
+	if m == nil {
+		return tomlSpan{}, errors.New("could not find __version__ assignment in Python module")
+	}
+	if m[4] >= 0 {
+		return tomlSpan{start: m[4], end: m[5]}, nil
+	}
+	return tomlSpan{start: m[6], end: m[7]}, nil
+}
+
+// parseGradleProperties (text) uses regex reGradlePropertiesVersion to FindSubmatch on the content and return
+// v.scan(matches[2]) of the result
+func (v *Version) parseGradleProperties(content []byte) error {
+	matches := reGradlePropertiesVersion.FindSubmatch(content)
+	if len(matches) < 3 {
+		return errors.New("could not find version property in gradle.properties")
+	}
+	return v.scan(matches[2])
+}
+
+// parseBuildGradle (text) uses regex reBuildGradleVersion to FindSubmatch on the content and return v.scan(matches[3])
+// of the result
+func (v *Version) parseBuildGradle(content []byte) error {
+	matches := reBuildGradleVersion.FindSubmatch(content)
+	if len(matches) < 4 {
+		return errors.New("could not find version assignment in build.gradle")
+	}
+	return v.scan(matches[3])
+}
+
+// parseMSBuild (text) uses regex reMSBuildVersion to find the first <Version> element of a <PropertyGroup>, or
+// reMSBuildVersionPrefix and reMSBuildVersionSuffix to combine <VersionPrefix>-<VersionSuffix>, and returns v.scan() of
+// the result; the <Version> metadata of items such as a <PackageReference> is never read
+func (v *Version) parseMSBuild(content []byte) error {
+	if loc := msbuildProperty(content, reMSBuildVersion); loc != nil {
+		return v.scan(bytes.TrimSpace(content[loc[4]:loc[5]]))
+	}
+	prefix := msbuildProperty(content, reMSBuildVersionPrefix)
+	if prefix == nil {
+		return errors.New("could not find <Version> or <VersionPrefix> element in project file")
+	}
+	version := bytes.TrimSpace(content[prefix[6]:prefix[7]])
+	if suffix := msbuildProperty(content, reMSBuildVersionSuffix); suffix != nil {
+		if s := bytes.TrimSpace(content[suffix[4]:suffix[5]]); len(s) > 0 {
+			version = append(append(slices.Clip(version), '-'), s...)
+		}
+	}
+	return v.scan(version)
+}
+
+// msbuildProperty returns the submatch indexes of the first match of re inside a <PropertyGroup> of the project file
+// content, or nil when no <PropertyGroup> has one
+func msbuildProperty(content []byte, re *regexp.Regexp) []int {
+	for _, group := range reMSBuildPropertyGroup.FindAllIndex(content, -1) {
+		loc := re.FindSubmatchIndex(content[group[0]:group[1]])
+		if loc == nil {
+			continue
+		}
+		for i := range loc {
+			if loc[i] >= 0 {
+				loc[i] += group[0]
+			}
+		}
+		return loc
+	}
+	return nil
+}
+
+// parseAssemblyInfo (text) uses regex reAssemblyVersion to FindSubmatch on the content and return v.scan(matches[2]) of
+// the first AssemblyVersion or AssemblyFileVersion attribute
+func (v *Version) parseAssemblyInfo(content []byte) error {
+	matches := reAssemblyVersion.FindSubmatch(content)
+	if len(matches) < 3 {
+		return errors.New("could not find AssemblyVersion attribute in AssemblyInfo.cs")
+	}
+	return v.scan(matches[2])
+}
+
+// goVersion returns the Go version of the go source of the Version, which is used for a -fix or a save of an -in go.mod
+// file; GoSourceAuto uses ~/go/version from the IGO "golang version manager" when installed, otherwise the local toolchain
+func (v *Version) goVersion() (string, error) {
+	switch v.goSource {
+	case "", GoSourceAuto:
+		if igoVersion, err := currentIgoVersion(); err == nil {
+			return igoVersion, nil
+		}
+		return currentGoVersion()
+	case GoSourceLocal:
+		return currentGoVersion()
+	case GoSourceIgo:
+		return currentIgoVersion()
+	case GoSourceToolchain:
+		m := reGoModToolchain.FindSubmatch(v.raw)
+		if m == nil {
+			return "", errors.New("could not find toolchain directive in go.mod")
+		}
+		return string(m[2]), nil
+	}
+	return reGoVersion.FindStringSubmatch(v.goSource)[1], nil
 }
@@ -0,0 +1,99 @@
+package bump
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SetPreRelease replaces the pre-release identifiers of the Version with the dot-separated identifiers provided
+//
+// Example:
+// 		v, _ := bump.Parse("v1.2.3")
+// 		err := v.SetPreRelease("nightly.20261017") // v1.2.3-nightly.20261017
+func (v *Version) SetPreRelease(preRelease string) error {
+	v.safety()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	ids, err := splitIdentifiers(preRelease)
+	if err != nil {
+		return err
+	}
+	if v.app != nil {
+		if err := v.app.SetPreRelease(preRelease); err != nil {
+			return err
+		}
+	}
+	v.setPreReleaseForm(v.applyPreRelease(ids))
+	return nil
+}
+
+// splitIdentifiers breaks a dot-separated pre-release string into its identifiers and validates each of them
+func splitIdentifiers(preRelease string) ([]string, error) {
+	preRelease = strings.TrimPrefix(preRelease, "-")
+	if len(preRelease) == 0 {
+		return nil, nil
+	}
+	ids := strings.Split(preRelease, ".")
+	for _, id := range ids {
+		if !reIdentifier.MatchString(id) {
+			return nil, fmt.Errorf("invalid pre-release identifier %q in %q", id, preRelease)
+		}
+	}
+	return ids, nil
+}
+
+// identifiers returns the pre-release identifiers of the Version, preferring the Alpha, Beta, RC and Preview
+// convenience fields over the PreRelease list when any of them are set
+func (v *Version) identifiers() []string {
+	switch {
+	case v.Preview > 0:
+		return []string{"preview", strconv.Itoa(v.Preview)}
+	case v.RC > 0:
+		return []string{"rc", strconv.Itoa(v.RC)}
+	case v.Beta > 0 && v.Alpha > 0:
+		return []string{"beta", strconv.Itoa(v.Beta) + "-alpha", strconv.Itoa(v.Alpha)}
+	case v.Beta > 0:
+		return []string{"beta", strconv.Itoa(v.Beta)}
+	case v.Alpha > 0:
+		return []string{"alpha", strconv.Itoa(v.Alpha)}
+	}
+	return v.PreRelease
+}
+
+// applyPreRelease stores the identifiers into PreRelease and maps the named channels bump knows about onto the
+// Alpha, Beta, RC and Preview fields, returning the Form that renders them (or "" when only PreRelease can)
+func (v *Version) applyPreRelease(ids []string) string {
+	v.Alpha, v.Beta, v.RC, v.Preview = 0, 0, 0, 0
+	v.PreRelease = ids
+	if len(ids) == 3 && ids[0] == "beta" && strings.HasSuffix(ids[1], "-alpha") {
+		beta, err1 := strconv.Atoi(strings.TrimSuffix(ids[1], "-alpha"))
+		alpha, err2 := strconv.Atoi(ids[2])
+		if err1 == nil && err2 == nil {
+			v.Beta, v.Alpha = beta, alpha
+			return FormE
+		}
+	}
+	if len(ids) != 2 {
+		return ""
+	}
+	n, err := strconv.Atoi(ids[1])
+	if err != nil {
+		return ""
+	}
+	switch ids[0] {
+	case "alpha":
+		v.Alpha = n
+		return FormB
+	case "beta":
+		v.Beta = n
+		return FormC
+	case "rc":
+		v.RC = n
+		return FormD
+	case "preview":
+		v.Preview = n
+		return FormF
+	}
+	return ""
+}
@@ -5,20 +5,83 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
-	"os"
 	"path/filepath"
+	"strings"
 )
 
-// Save passes through based on the filepath.Base(path) provided
+// Save writes the Version into path, along with every other file that Render returns for it, using WriteFiles so that
+// either every file is updated or none is
 func (v *Version) Save(path string) error {
+	files, err := v.Render(path)
+	if err != nil {
+		return err
+	}
+	return WriteFiles(files)
+}
+
+// SaveAll saves every Version into the file it was loaded from using a single WriteFiles, so that the version of
+// several files is updated in lockstep: either every file is updated or none is
+//
+// Example:
+// 		a, b := bump.New(), bump.New()
+// 		err := a.ParseFile("VERSION")
+// 		err = b.ParseFile("package.json")
+// 		a.BumpMinor()
+// 		b.Assign(a)
+// 		err = bump.SaveAll(a, b)
+func SaveAll(versions ...*Version) error {
+	files, err := RenderAll(versions...)
+	if err != nil {
+		return err
+	}
+	return WriteFiles(files)
+}
+
+// RenderAll returns the new content of every file that SaveAll writes for the versions without writing any of them, so
+// that other files can join the same WriteFiles
+func RenderAll(versions ...*Version) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	for _, v := range versions {
+		path := v.Path()
+		rendered, err := v.Render(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot save %s: %w", path, err)
+		}
+		for p, content := range rendered {
+			if existing, ok := files[p]; ok && !bytes.Equal(existing, content) {
+				return nil, fmt.Errorf("cannot save %s: it would be written twice with different content", p)
+			}
+			files[p] = content
+		}
+	}
+	return files, nil
+}
+
+// Render returns the new content of every file that Save writes for path without writing any of them, which is path
+// itself and, for a pom.xml, the reactor modules that reference its version
+func (v *Version) Render(path string) (map[string][]byte, error) {
 	v.safety()
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	v.path = path
-	base := filepath.Base(v.path)
+	files := make(map[string][]byte)
+	v.pending = files
+	defer func() { v.pending = nil }()
+	if err := v.render(); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// write records content as the new content of the file at path for Render
+func (v *Version) write(path string, content []byte) error {
+	v.pending[path] = content
+	return nil
+}
 
-	switch base {
+// render passes through based on the filepath.Base(path) of the Version
+func (v *Version) render() error {
+	switch fileKind(filepath.Base(v.path)) {
 	case FileVersion:
 		return v.saveVersion()
 	case FilePackageJson:
@@ -31,82 +94,242 @@ func (v *Version) Save(path string) error {
 		return v.saveMavenPom()
 	case FileHelmChart:
 		return v.saveHelmChart()
+	case FileCargoToml:
+		return v.saveCargoToml()
+	case FilePyProject:
+		return v.savePython(pyProjectVersion)
+	case FileSetupCfg:
+		return v.savePython(setupCfgVersion)
+	case FilePython:
+		return v.savePython(pythonVersion)
+	case FileGradleProperties:
+		return v.saveGradleProperties()
+	case FileBuildGradle, FileBuildGradleKts:
+		return v.saveBuildGradle()
+	case FileCsproj, FileBuildProps:
+		return v.saveMSBuild()
+	case FileAssemblyInfo:
+		return v.saveAssemblyInfo()
 	default:
 		return v.saveVersion()
 	}
 }
 
-// saveVersion passes into os.WriteFile on the path and the v.format(!v.noPrefix) with 0644 permissions
+// saveVersion passes into v.write on the path and the v.format(!v.noPrefix)
 func (v *Version) saveVersion() error {
-	return os.WriteFile(v.path, []byte(v.format(!v.noPrefix)), 0644)
+	return v.write(v.path, []byte(v.format(!v.noPrefix)))
 }
 
-// savePackageJson assigns to the parsed value the v.format(false) to the "version" key before json.MarshalIndent the
-// output before sending that into os.WriteFile on the path provided
+// savePackageJson uses jsonValue to locate the top-level "version" value in raw and replaces only that value with
+// v.format(false), keeping the key order, indentation and final newline, before sending it to v.write on the path
+// provided; a missing or empty file is initialized with a new object
 func (v *Version) savePackageJson() error {
-	if v.parsed == nil {
-		return errors.New("cannot save package.json: file was not parsed")
+	v.useForm = ""
+	newVersion := v.format(false)
+	if len(bytes.TrimSpace(v.raw)) == 0 {
+		output, err := json.MarshalIndent(map[string]string{"version": newVersion}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("could not marshal version info: %w", err)
+		}
+		return v.write(v.path, append(output, '\n'))
 	}
-	v.useForm = FormG
-	v.parsed["version"] = v.format(false)
-	output, err := json.MarshalIndent(v.parsed, "", "  ")
+	span, err := jsonValue(v.raw, "version")
 	if err != nil {
-		return fmt.Errorf("could not marshal version info: %w", err)
+		return fmt.Errorf("cannot save package.json: %w", err)
 	}
-	return os.WriteFile(v.path, output, 0644)
+	return v.write(v.path, replaceSpan(v.raw, span, newVersion))
 }
 
-// saveHelmChart assigns tot he parsed value of the v.format(false) to the "version", uses yaml.NewEncoder on those bytes
-// and then uses os.WriteFile to save the bytes to the path provided, the full parsed output
+// saveHelmChart uses yamlScalar to locate the "version" and "appVersion" values in raw and replaces only those that
+// the appVersion policy updates, keeping their quotes, the comments and the key order, before sending it to
+// v.write on the path provided
 func (v *Version) saveHelmChart() error {
-	if v.parsed == nil {
-		return errors.New("cannot save Chart.yaml: file was not parsed")
-	}
+	versionSpan, _, versionErr := yamlScalar(v.raw, "version")
+	appSpan, appNode, appErr := yamlScalar(v.raw, "appVersion")
 	v.useForm = ""
-	newVersion := v.format(false)
-	v.parsed["version"] = newVersion
-	//  if _, ok := v.parsed["appVersion"]; ok {
-	//		v.parsed["appVersion"] = newVersion
-	//	}
-	var buf bytes.Buffer
-	encoder := yaml.NewEncoder(&buf)
-	encoder.SetIndent(2)
-	if err := encoder.Encode(v.parsed); err != nil {
-		return fmt.Errorf("could not marshal helm chart: %w", err)
+	var changes []spanChange
+	if v.appPolicy != AppVersionOnly {
+		if versionErr != nil {
+			return fmt.Errorf("cannot save Chart.yaml: %w", versionErr)
+		}
+		changes = append(changes, spanChange{span: versionSpan, value: v.format(false)})
 	}
-	return os.WriteFile(v.path, buf.Bytes(), 0644)
+	if v.appPolicy != AppVersionIgnore && len(v.appPolicy) > 0 {
+		if appErr != nil {
+			return fmt.Errorf("cannot save Chart.yaml: %w", appErr)
+		}
+		appVersion := v.format(false)
+		if v.app != nil {
+			v.app.useForm = ""
+			appVersion = v.app.format(false)
+		}
+		if strings.HasPrefix(appNode.Value, "v") {
+			appVersion = "v" + appVersion
+		}
+		changes = append(changes, spanChange{span: appSpan, value: appVersion})
+	}
+	return v.write(v.path, replaceSpans(v.raw, changes...))
 }
 
-// saveDockerfile replaces in raw using regex reDockerfileVersion to replace the LABEL provided in the Dockerfile file
-// before running os.WriteFile on the provided path
+// saveDockerfile replaces in raw every LABEL, ARG and ENV value located by dockerVersion, keeping the "v" prefix and
+// quotes of each of them, before running v.write on the provided path
 func (v *Version) saveDockerfile() error {
+	_, all, err := dockerVersion(v.raw, v.dockerKey)
+	if err != nil {
+		return err
+	}
 	v.useForm = ""
-	newVersion := v.format(true)
-	newContent := reDockerfileVersion.ReplaceAll(v.raw, []byte("${1}"+newVersion+"${3}"))
-	return os.WriteFile(v.path, newContent, 0644)
+	newVersion := v.format(false)
+	changes := make([]spanChange, 0, len(all))
+	for _, d := range all {
+		value := newVersion
+		if strings.HasPrefix(d.value, "v") {
+			value = "v" + newVersion
+		}
+		changes = append(changes, spanChange{span: d.span, value: value})
+	}
+	return v.write(v.path, replaceSpans(v.raw, changes...))
 }
 
-// saveGoMod replaces in raw using regex reGoModVersion to replace the "go #.#[.#]" with the new version before sending
-// that to os.WriteFile on the provided path
+// saveGoMod replaces in raw using regex reGoModVersion to replace the "go #.#[.#]" with the version of the go source
+// before sending that to v.write on the provided path; a "toolchain" directive older than the new "go" is refused
 func (v *Version) saveGoMod() error {
-	err := v.parseIgo()
+	goVersion, err := v.goVersion()
 	if err != nil {
 		return err
 	}
+	if err := v.parseVersion([]byte(goVersion)); err != nil {
+		return err
+	}
 	v.useForm = FormG
 	newVersion := v.format(false)
+	if m := reGoModToolchain.FindSubmatch(v.raw); m != nil {
+		toolchain, err := Parse(string(m[2]))
+		if err != nil {
+			return fmt.Errorf("invalid toolchain directive in go.mod: %w", err)
+		}
+		if toolchain.Compare(v) < 0 {
+			return fmt.Errorf("toolchain go%s in go.mod is older than go %s", m[2], newVersion)
+		}
+	}
 	newContent := reGoModVersion.ReplaceAll(v.raw, []byte("${1}"+newVersion))
-	return os.WriteFile(v.path, newContent, 0644)
+	return v.write(v.path, newContent)
 }
 
-// saveMavenPom replaces in raw using regex reMavenVersion to replace the <project>..<version> tag in pom.xml before
-// sending it to os.WriteFile on the provided path
+// saveMavenPom replaces in raw only the <version> located by parsePom (or the <properties> value it references) before
+// sending it to v.write on the provided path; when the project version changes, the <parent><version> of the
+// reactor modules that reference the old version is updated as well
 func (v *Version) saveMavenPom() error {
+	p, err := parsePom(v.raw)
+	if err != nil {
+		return err
+	}
+	span, err := p.version(v.pomTarget)
+	if err != nil {
+		return err
+	}
 	v.useForm = ""
-	newVersion := v.format(false)
-	if !reMavenVersion.Match(v.raw) {
-		return errors.New("could not find <project>...<version> tag in pom.xml to update")
+	old, newVersion := string(v.raw[span.start:span.end]), v.format(false)
+	if err := v.write(v.path, replaceSpan(v.raw, span, newVersion)); err != nil {
+		return err
+	}
+	if v.pomTarget == PomParent {
+		return nil
+	}
+	return v.saveMavenModules(filepath.Dir(v.path), p, old, newVersion)
+}
+
+// saveCargoToml replaces in raw only the version string located by cargoVersion, preserving the comments, key order and
+// formatting of the rest of the Cargo.toml, before sending it to v.write on the provided path
+func (v *Version) saveCargoToml() error {
+	span, err := cargoVersion(v.raw)
+	if err != nil {
+		return err
+	}
+	v.useForm = ""
+	newContent := replaceSpan(v.raw, span, v.format(false))
+	return v.write(v.path, newContent)
+}
+
+// savePython replaces in raw only the version located by locate with the PEP 440 spelling of the Version, preserving the
+// rest of the pyproject.toml, setup.cfg or Python module byte-for-byte, before sending it to v.write on the path
+func (v *Version) savePython(locate func([]byte) (tomlSpan, error)) error {
+	span, err := locate(v.raw)
+	if err != nil {
+		return err
+	}
+	v.pep440 = true
+	newContent := replaceSpan(v.raw, span, v.format(false))
+	return v.write(v.path, newContent)
+}
+
+// saveGradleProperties replaces in raw using regex reGradlePropertiesVersion to replace the first version= property in
+// gradle.properties before sending it to v.write on the provided path
+func (v *Version) saveGradleProperties() error {
+	loc := reGradlePropertiesVersion.FindSubmatchIndex(v.raw)
+	if loc == nil {
+		return errors.New("could not find version property in gradle.properties to update")
+	}
+	v.useForm = keepFourPart(v.useForm)
+	newContent := replaceSpan(v.raw, tomlSpan{start: loc[4], end: loc[5]}, v.format(false))
+	return v.write(v.path, newContent)
+}
+
+// saveBuildGradle replaces in raw using regex reBuildGradleVersion to replace the first version = "..." assignment in
+// build.gradle or build.gradle.kts before sending it to v.write on the provided path
+func (v *Version) saveBuildGradle() error {
+	loc := reBuildGradleVersion.FindSubmatchIndex(v.raw)
+	if loc == nil {
+		return errors.New("could not find version assignment in build.gradle to update")
+	}
+	v.useForm = keepFourPart(v.useForm)
+	newContent := replaceSpan(v.raw, tomlSpan{start: loc[6], end: loc[7]}, v.format(false))
+	return v.write(v.path, newContent)
+}
+
+// saveMSBuild replaces in raw the first <Version> element of a <PropertyGroup>, or the <VersionPrefix> and
+// <VersionSuffix> elements (adding the suffix after the prefix when a pre-release needs one), before sending it to
+// v.write on the provided path; item metadata such as the <Version> of a <PackageReference> is left untouched
+func (v *Version) saveMSBuild() error {
+	v.useForm = keepFourPart(v.useForm)
+	if loc := msbuildProperty(v.raw, reMSBuildVersion); loc != nil {
+		newContent := replaceSpan(v.raw, tomlSpan{start: loc[4], end: loc[5]}, v.format(false))
+		return v.write(v.path, newContent)
+	}
+	loc := msbuildProperty(v.raw, reMSBuildVersionPrefix)
+	if loc == nil {
+		return errors.New("could not find <Version> or <VersionPrefix> element in project file to update")
+	}
+	newContent := replaceSpan(v.raw, tomlSpan{start: loc[6], end: loc[7]}, v.formatCore())
+	suffix := strings.Join(v.identifiers(), ".")
+	if sloc := msbuildProperty(newContent, reMSBuildVersionSuffix); sloc != nil {
+		newContent = replaceSpan(newContent, tomlSpan{start: sloc[4], end: sloc[5]}, suffix)
+	} else if len(suffix) > 0 {
+		loc = msbuildProperty(newContent, reMSBuildVersionPrefix)
+		indent := string(newContent[loc[2]:loc[3]])
+		newContent = replaceSpan(newContent, tomlSpan{start: loc[9], end: loc[9]},
+			"\n"+indent+"<VersionSuffix>"+suffix+"</VersionSuffix>")
+	}
+	return v.write(v.path, newContent)
+}
+
+// saveAssemblyInfo replaces in raw the numeric AssemblyVersion and AssemblyFileVersion attributes with the core version
+// and any AssemblyInformationalVersion attribute with the full version before sending it to v.write on the path
+func (v *Version) saveAssemblyInfo() error {
+	if !reAssemblyVersion.Match(v.raw) {
+		return errors.New("could not find AssemblyVersion attribute in AssemblyInfo.cs to update")
+	}
+	v.useForm = keepFourPart(v.useForm)
+	newContent := reAssemblyVersion.ReplaceAll(v.raw, []byte("${1}"+v.formatCore()+"${3}"))
+	newContent = reAssemblyInformationalVersion.ReplaceAll(newContent, []byte("${1}"+v.format(false)+"${3}"))
+	return v.write(v.path, newContent)
+}
+
+// keepFourPart returns FormK when form renders a four part version, so the revision survives a save without a prefix,
+// or "" to let format render the version with its pre-release identifiers
+func keepFourPart(form string) string {
+	if form == FormK || form == FormL {
+		return FormK
 	}
-	newContent := reMavenVersion.ReplaceAll(v.raw, []byte("${1}"+newVersion+"${3}"))
-	return os.WriteFile(v.path, newContent, 0644)
+	return ""
 }
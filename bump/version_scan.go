@@ -2,6 +2,7 @@ package bump
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -9,8 +10,17 @@ import (
 // successful, and Forms (of the formsInOrder as (t)) matches the number of assignments of the version components
 func (v *Version) scan(raw []byte) error {
 	v.Major, v.Minor, v.Patch, v.Alpha, v.Beta, v.RC, v.Preview = 0, 0, 0, 0, 0, 0, 0
+	v.Revision = 0
+	v.PreRelease, v.Build = nil, nil
+	v.pep440, v.pep440Len = false, 0
 
 	rawStr := string(raw)
+	if ok, err := v.scanSemVer(rawStr); ok || err != nil {
+		return err
+	}
+	if ok, err := v.scanPep440(rawStr); ok || err != nil {
+		return err
+	}
 	for _, t := range formsInOrder {
 		var n int
 		var err error
@@ -37,11 +47,14 @@ func (v *Version) scan(raw []byte) error {
 			n, err = fmt.Sscanf(rawStr, t, &tempV.Major)
 		case FormJ:
 			n, err = fmt.Sscanf(rawStr, t, &tempV.Major, &tempV.Minor)
+		case FormK, FormL:
+			n, err = fmt.Sscanf(rawStr, t, &tempV.Major, &tempV.Minor, &tempV.Patch, &tempV.Revision)
 		}
 
 		if err == nil && n == Forms[t] {
 			v.Major, v.Minor, v.Patch = tempV.Major, tempV.Minor, tempV.Patch
 			v.Alpha, v.Beta, v.RC, v.Preview = tempV.Alpha, tempV.Beta, tempV.RC, tempV.Preview
+			v.Revision = tempV.Revision
 			v.useForm = t
 			v.noPrefix = strings.HasPrefix(t, "%d")
 			return nil
@@ -49,3 +62,102 @@ func (v *Version) scan(raw []byte) error {
 	}
 	return fmt.Errorf("unrecognized version format: \"%s\"", rawStr)
 }
+
+// scanSemVer matches rawStr against reSemVer and, when it is a spec-valid SemVer string, assigns the version components
+// pre-release identifiers and build metadata, keeping the legacy Form for the named channels so the string round-trips unchanged
+func (v *Version) scanSemVer(rawStr string) (bool, error) {
+	m := reSemVer.FindStringSubmatch(rawStr)
+	if m == nil {
+		return false, nil
+	}
+	var parts [3]int
+	for i := range parts {
+		n, err := strconv.Atoi(m[i+2])
+		if err != nil {
+			return true, fmt.Errorf("invalid version component %q in \"%s\": %w", m[i+2], rawStr, err)
+		}
+		parts[i] = n
+	}
+	v.Major, v.Minor, v.Patch = parts[0], parts[1], parts[2]
+	v.noPrefix = len(m[1]) == 0
+	v.pep440Len = 3
+	if len(m[4]) == 0 {
+		v.pep440Len = 2
+	}
+
+	var ids []string
+	if len(m[5]) > 0 {
+		ids = strings.Split(m[5], ".")
+	}
+	if len(m[6]) > 0 {
+		v.Build = strings.Split(m[6], ".")
+	}
+	form := v.applyPreRelease(ids)
+	switch {
+	case len(ids) == 0 && v.noPrefix:
+		v.useForm = FormG
+	case len(ids) == 0:
+		v.useForm = FormA
+	case v.noPrefix:
+		v.useForm = ""
+	default:
+		v.useForm = form
+	}
+	return true, nil
+}
+
+// pep440Channels maps the PEP 440 pre-release spellings onto the named channels of bump
+var pep440Channels = map[string]string{
+	"a": "alpha", "alpha": "alpha",
+	"b": "beta", "beta": "beta",
+	"c": "rc", "rc": "rc", "pre": "rc", "preview": "rc",
+}
+
+// scanPep440 matches rawStr against rePep440 and maps PEP 440 pre-release (a, b, rc) and development (.devN) segments
+// onto the Alpha, Beta and RC fields or the PreRelease identifiers, rendering them back in PEP 440 spelling
+func (v *Version) scanPep440(rawStr string) (bool, error) {
+	m := rePep440.FindStringSubmatch(rawStr)
+	if m == nil || (len(m[5]) == 0 && len(m[7]) == 0) {
+		return false, nil
+	}
+	var parts [3]int
+	for i := range parts {
+		if len(m[i+2]) == 0 {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+2])
+		if err != nil {
+			return true, fmt.Errorf("invalid version component %q in \"%s\": %w", m[i+2], rawStr, err)
+		}
+		parts[i] = n
+	}
+	v.Major, v.Minor, v.Patch = parts[0], parts[1], parts[2]
+	v.noPrefix = len(m[1]) == 0
+	v.pep440Len = 3
+	if len(m[4]) == 0 {
+		v.pep440Len = 2
+	}
+
+	var ids []string
+	for _, segment := range [][2]string{{m[5], m[6]}, {m[7], m[8]}} {
+		if len(segment[0]) == 0 {
+			continue
+		}
+		name := strings.ToLower(segment[0])
+		if channel, ok := pep440Channels[name]; ok {
+			name = channel
+		}
+		n := 0 // PEP 440 implicit pre-release number
+		if len(segment[1]) > 0 {
+			var err error
+			if n, err = strconv.Atoi(segment[1]); err != nil {
+				return true, fmt.Errorf("invalid %s number %q in \"%s\": %w", name, segment[1], rawStr, err)
+			}
+		}
+		ids = append(ids, name, strconv.Itoa(n))
+	}
+	v.applyPreRelease(ids)
+	v.useForm = ""
+	v.pep440 = true
+	return true, nil
+}
@@ -3,8 +3,11 @@ package bump
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -57,6 +60,7 @@ func TestAllBumps(t *testing.T) {
 		assert.NoError(t, err)
 		v.BumpBeta()
 		assert.Equal(t, 5, v.Beta, "Beta should be incremented")
+		assert.Equal(t, "v1.2.3-beta.5", v.String(), "Beta should render the beta form")
 	})
 
 	t.Run("BumpAlpha", func(t *testing.T) {
@@ -71,6 +75,32 @@ func TestAllBumps(t *testing.T) {
 		assert.NoError(t, err)
 		v.BumpPreview()
 		assert.Equal(t, 5, v.Preview, "Preview should be incremented")
+		assert.Equal(t, 3, v.Patch, "Patch should be kept")
+	})
+
+	t.Run("Unprefixed", func(t *testing.T) {
+		testCases := []struct {
+			input    string
+			bumpFunc func(*Version)
+			expected string
+		}{
+			{"1.2.3-rc.1", (*Version).BumpRC, "1.2.3-rc.2"},
+			{"1.2.3-alpha.1", (*Version).BumpRC, "1.2.3-rc.1"},
+			{"1.2.3", (*Version).BumpAlpha, "1.2.3-alpha.1"},
+			{"1.2.3-alpha.1", (*Version).BumpAlpha, "1.2.3-alpha.2"},
+			{"1.2.3", (*Version).BumpBeta, "1.2.3-beta.1"},
+			{"1.2.3-alpha.2", (*Version).BumpBeta, "1.2.3-beta.1-alpha.2"},
+			{"1.2.3-beta.1", (*Version).BumpAlpha, "1.2.3-beta.1-alpha.1"},
+			{"1.2.3", (*Version).BumpPreview, "1.2.3-preview.1"},
+			{"1.2.3-preview.1", (*Version).BumpPreview, "1.2.3-preview.2"},
+		}
+		for _, tc := range testCases {
+			v, err := Parse(tc.input)
+			assert.NoError(t, err, tc.input)
+			tc.bumpFunc(v)
+			assert.Equal(t, tc.expected, v.String(), tc.input)
+			assert.Equal(t, tc.expected, v.Format(true), tc.input)
+		}
 	})
 }
 
@@ -97,6 +127,67 @@ func TestFormatting(t *testing.T) {
 	}
 }
 
+// TestPreReleaseRoundTrip verifies that spec-valid SemVer pre-release identifiers parse and format unchanged.
+func TestPreReleaseRoundTrip(t *testing.T) {
+	testCases := []struct {
+		input      string
+		preRelease []string
+	}{
+		{"v1.2.3-alpha.1.x7", []string{"alpha", "1", "x7"}},
+		{"v1.2.3-dev", []string{"dev"}},
+		{"v1.2.3-0.3.7", []string{"0", "3", "7"}},
+		{"v1.2.3-nightly.20261017", []string{"nightly", "20261017"}},
+		{"1.2.3-x-y-z.--", []string{"x-y-z", "--"}},
+		{"1.0.0-rc.1", []string{"rc", "1"}},
+		{"v1.0.0-alpha.0", []string{"alpha", "0"}},
+		{"v1.2.3-beta.4-alpha.5", []string{"beta", "4-alpha", "5"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			v, err := Parse(tc.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.preRelease, v.PreRelease)
+			assert.Equal(t, tc.input, v.String())
+		})
+	}
+
+	t.Run("SetPreRelease", func(t *testing.T) {
+		v, err := Parse("v1.2.3")
+		assert.NoError(t, err)
+		assert.NoError(t, v.SetPreRelease("rc.2"))
+		assert.Equal(t, 2, v.RC)
+		assert.Equal(t, "v1.2.3-rc.2", v.String())
+		assert.Error(t, v.SetPreRelease("alpha.01"))
+		assert.Error(t, v.SetPreRelease("alpha..1"))
+	})
+}
+
+// TestBuildMetadata verifies that build metadata parses, formats, clears on bump and is ignored by Compare.
+func TestBuildMetadata(t *testing.T) {
+	v, err := Parse("v1.4.0+20261017.abc1234")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"20261017", "abc1234"}, v.Build)
+	assert.Equal(t, "v1.4.0+20261017.abc1234", v.String())
+
+	o, err := Parse("1.4.0+exp.sha.5114f85")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, v.Compare(o), "build metadata must not affect precedence")
+
+	pre, err := Parse("v1.4.0-rc.1+001")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.4.0-rc.1+001", pre.String())
+	assert.Equal(t, -1, pre.Compare(v))
+
+	assert.NoError(t, v.SetBuild("build.7"))
+	assert.Equal(t, "v1.4.0+build.7", v.String())
+	assert.Error(t, v.SetBuild("bad..meta"))
+	assert.Error(t, v.SetBuild("under_score"))
+
+	v.BumpPatch()
+	assert.Empty(t, v.Build, "bumping should clear build metadata")
+	assert.Equal(t, "v1.4.1", v.String())
+}
+
 // TestCompare verifies the version comparison logic.
 func TestCompare(t *testing.T) {
 	testCases := []struct {
@@ -120,6 +211,117 @@ func TestCompare(t *testing.T) {
 	}
 }
 
+// TestComparePrecedence is a conformance table built from the SemVer 2.0.0 §11 examples on semver.org.
+func TestComparePrecedence(t *testing.T) {
+	ascending := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11",
+		"1.0.0-rc.1", "1.0.0", "2.0.0", "2.1.0", "2.1.1",
+	}
+	for i := range ascending {
+		for j := range ascending {
+			a, err := Parse(ascending[i])
+			assert.NoError(t, err)
+			b, err := Parse(ascending[j])
+			assert.NoError(t, err)
+			assert.Equal(t, compareInt(i, j), a.Compare(b), "%s <=> %s", ascending[i], ascending[j])
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		v1       string
+		v2       string
+		expected int
+	}{
+		{"Mixed Channels", "v1.0.0-rc.1", "v1.0.0-alpha.5", 1},
+		{"Preview Before RC", "v1.0.0-preview.9", "v1.0.0-rc.1", -1},
+		{"Beta Before Preview", "v1.0.0-beta.3", "v1.0.0-preview.1", -1},
+		{"Numeric Identifiers", "1.0.0-0.3.7", "1.0.0-0.3.10", -1},
+		{"Numeric Below Alphanumeric", "1.0.0-1", "1.0.0-a", -1},
+		{"Large Numeric", "1.0.0-99999999999999999999", "1.0.0-100000000000000000000", -1},
+		{"Build Ignored", "1.0.0+a", "1.0.0+b", 0},
+		{"Prefix Ignored", "v1.2.3-rc.1", "1.2.3-rc.1", 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := Parse(tc.v1)
+			assert.NoError(t, err)
+			b, err := Parse(tc.v2)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, a.Compare(b))
+			assert.Equal(t, -tc.expected, b.Compare(a))
+		})
+	}
+
+	t.Run("Configured Channels", func(t *testing.T) {
+		defer func(c []string) { Channels = c }(Channels)
+		Channels = []string{"dev", "alpha", "beta", "rc", "preview"}
+		a, _ := Parse("v1.0.0-preview.1")
+		b, _ := Parse("v1.0.0-rc.3")
+		c, _ := Parse("v1.0.0-dev.4")
+		d, _ := Parse("v1.0.0-alpha.1")
+		assert.Equal(t, 1, a.Compare(b))
+		assert.Equal(t, -1, c.Compare(d))
+	})
+}
+
+// TestConstraint verifies caret, tilde, wildcard, hyphen and comparison ranges.
+func TestConstraint(t *testing.T) {
+	testCases := []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{"^1.2", "v1.2.0", true},
+		{"^1.2", "v1.9.9", true},
+		{"^1.2", "v2.0.0", false},
+		{"^1.2", "v1.1.9", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3.0", false},
+		{"~1", "1.9.0", true},
+		{"1.x", "1.4.2", true},
+		{"1.2.*", "1.3.0", false},
+		{"*", "42.0.0", true},
+		{"1.2.3 - 2.3", "2.3.9", true},
+		{"1.2.3 - 2.3", "2.4.0", false},
+		{"1.2 - 2.3.4", "1.2.0", true},
+		{">=1.0.0 <2.0.0", "1.5.0", true},
+		{">=1.0.0, <2.0.0", "2.0.0", false},
+		{">= 1.0.0 < 2.0.0", "0.9.9", false},
+		{">1.2", "1.2.9", false},
+		{">1.2", "1.3.0", true},
+		{"<=1.2", "1.2.9", true},
+		{"<1.2", "1.2.0", false},
+		{"!=1.2.3", "1.2.3", false},
+		{"=1.2.3", "v1.2.3+build.5", true},
+		{"^1.2 || ^3.0", "3.1.0", true},
+		{"^1.2 || ^3.0", "2.1.0", false},
+		{"^1.2", "1.3.0-beta.1", false},
+		{"^1.2", "2.0.0-alpha.1", false},
+		{"^1.2.3-beta.2", "1.2.3-beta.4", true},
+		{"^1.2.3-beta.2", "1.2.3-alpha.9", false},
+		{"^1.2.3-beta.2", "1.2.4-beta.1", false},
+		{"^1.2.3-beta.2", "1.2.4", true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.constraint+" "+tc.version, func(t *testing.T) {
+			c, err := ParseConstraint(tc.constraint)
+			assert.NoError(t, err)
+			v, err := Parse(tc.version)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, c.Satisfies(v))
+		})
+	}
+
+	for _, bad := range []string{"^", "1.2.3 -", ">=a.b", "!=1.2", "~1.x-rc.1", "1.2.3 - ^2"} {
+		_, err := ParseConstraint(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
 // TestFix validates the logic for fixing malformed version strings.
 func TestFix(t *testing.T) {
 	testCases := []struct {
@@ -192,11 +394,19 @@ func TestParseAndSaveFileTypes(t *testing.T) {
 		{
 			name:           "Dockerfile alpha bump",
 			filename:       "Dockerfile",
-			initialContent: `FROM alpine\nLABEL version="v1.2.3"`,
+			initialContent: "FROM alpine\nLABEL version=\"v1.2.3\"",
 			initialVersion: Version{Major: 1, Minor: 2, Patch: 3},
 			bumpFunc:       (*Version).BumpAlpha,
 			finalVersion:   Version{Major: 1, Minor: 2, Patch: 3, Alpha: 1},
 		},
+		{
+			name:           "Cargo.toml minor bump",
+			filename:       "Cargo.toml",
+			initialContent: "[package]\nname = \"crate\"\nversion = \"1.2.3\"\n",
+			initialVersion: Version{Major: 1, Minor: 2, Patch: 3, noPrefix: true},
+			bumpFunc:       (*Version).BumpMinor,
+			finalVersion:   Version{Major: 1, Minor: 3, Patch: 0},
+		},
 		{
 			name:           "pom.xml patch bump",
 			filename:       "pom.xml",
@@ -239,10 +449,609 @@ func TestParseAndSaveFileTypes(t *testing.T) {
 	}
 }
 
+// TestHelmChart verifies that only the version scalar changes when a Chart.yaml is saved.
+func TestHelmChart(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name: "Plain",
+			content: "# Chart for the api\napiVersion: v2\nname: api # keep me\ndescription: |\n  A chart\n  for the api\n" +
+				"version: 1.2.3\nappVersion: \"1.2.3\"\ndependencies:\n  - name: redis\n    version: 17.0.0\n",
+			expected: "# Chart for the api\napiVersion: v2\nname: api # keep me\ndescription: |\n  A chart\n  for the api\n" +
+				"version: 1.3.0\nappVersion: \"1.2.3\"\ndependencies:\n  - name: redis\n    version: 17.0.0\n",
+		},
+		{
+			name:     "Quoted",
+			content:  "---\nname: \"api\"\nversion:   '1.2.3'   # chart version\n",
+			expected: "---\nname: \"api\"\nversion:   '1.3.0'   # chart version\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileHelmChart)
+			assert.NoError(t, os.WriteFile(path, []byte(tc.content), 0644))
+			v := New()
+			assert.NoError(t, v.ParseFile(path))
+			v.BumpMinor()
+			assert.NoError(t, v.Save(path))
+			b, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(b))
+		})
+	}
+
+	v := New()
+	v.path = FileHelmChart
+	v.raw = []byte("name: api\nversion:\n  major: 1\n")
+	assert.Error(t, v.Parse())
+}
+
+// TestHelmChartAppVersion verifies the appVersion policies of a Chart.yaml.
+func TestHelmChartAppVersion(t *testing.T) {
+	content := "apiVersion: v2\nname: api\nversion: 1.2.3 # chart\nappVersion: \"v4.0.0\"\n"
+	testCases := []struct {
+		policy   string
+		check    string
+		expected string
+	}{
+		{AppVersionIgnore, "1.2.3", "apiVersion: v2\nname: api\nversion: 1.3.0 # chart\nappVersion: \"v4.0.0\"\n"},
+		{AppVersionSync, "1.2.3", "apiVersion: v2\nname: api\nversion: 1.3.0 # chart\nappVersion: \"v1.3.0\"\n"},
+		{AppVersionDerive, "1.2.3", "apiVersion: v2\nname: api\nversion: 1.3.0 # chart\nappVersion: \"v4.1.0\"\n"},
+		{AppVersionOnly, "v4.0.0", "apiVersion: v2\nname: api\nversion: 1.2.3 # chart\nappVersion: \"v4.1.0\"\n"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.policy, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileHelmChart)
+			assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			v := New()
+			assert.NoError(t, v.SetAppVersionPolicy(tc.policy))
+			assert.NoError(t, v.ParseFile(path))
+			assert.Equal(t, tc.check, v.Format(!v.NoPrefix()))
+			v.BumpMinor()
+			assert.NoError(t, v.Save(path))
+			b, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(b))
+		})
+	}
+
+	assert.Error(t, New().SetAppVersionPolicy("lockstep"))
+	v := New()
+	assert.NoError(t, v.SetAppVersionPolicy(AppVersionSync))
+	v.path = FileHelmChart
+	v.raw = []byte("name: api\nversion: 1.2.3\n")
+	assert.NoError(t, v.Parse())
+	assert.Error(t, v.Save(filepath.Join(t.TempDir(), FileHelmChart)))
+}
+
+// TestPackageJson verifies that only the top-level version changes when a package.json is saved.
+func TestPackageJson(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name: "Spaces",
+			content: "{\n    \"name\": \"app\",\n    \"engines\": {\"version\": \"1.0.0\"},\n    \"version\": \"1.2.3\",\n" +
+				"    \"scripts\": {\"test\": \"a && b > c\"}\n}\n",
+			expected: "{\n    \"name\": \"app\",\n    \"engines\": {\"version\": \"1.0.0\"},\n    \"version\": \"1.3.0\",\n" +
+				"    \"scripts\": {\"test\": \"a && b > c\"}\n}\n",
+		},
+		{
+			name:     "Tabs",
+			content:  "{\r\n\t\"version\" : \"1.2.3\",\r\n\t\"name\": \"<app>\"\r\n}",
+			expected: "{\r\n\t\"version\" : \"1.3.0\",\r\n\t\"name\": \"<app>\"\r\n}",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FilePackageJson)
+			assert.NoError(t, os.WriteFile(path, []byte(tc.content), 0644))
+			v := New()
+			assert.NoError(t, v.ParseFile(path))
+			v.BumpMinor()
+			assert.NoError(t, v.Save(path))
+			b, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(b))
+		})
+	}
+
+	_, err := jsonValue([]byte(`{"name": "app", "config": {"version": "1.0.0"}}`), "version")
+	assert.Error(t, err)
+}
+
+// TestMavenPom verifies the project, parent, ${revision} and reactor handling of a pom.xml.
+func TestMavenPom(t *testing.T) {
+	parent := "  <parent>\n    <groupId>org.example</groupId>\n    <artifactId>base</artifactId>\n    <version>7.0.0</version>\n  </parent>\n"
+	root := "<?xml version=\"1.0\"?>\n<project>\n" + parent + "  <groupId>com.example</groupId>\n  <artifactId>app</artifactId>\n" +
+		"  <version>1.2.3-SNAPSHOT</version>\n  <modules>\n    <module>core</module>\n  </modules>\n" +
+		"  <dependencies>\n    <dependency><version>9.9.9</version></dependency>\n  </dependencies>\n</project>\n"
+	core := "<project>\n  <parent>\n    <groupId>com.example</groupId>\n    <artifactId>app</artifactId>\n" +
+		"    <version>1.2.3-SNAPSHOT</version>\n  </parent>\n  <artifactId>core</artifactId>\n</project>\n"
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, FileMavenPom)
+	assert.NoError(t, os.WriteFile(path, []byte(root), 0644))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "core"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "core", FileMavenPom), []byte(core), 0644))
+
+	v := New()
+	assert.NoError(t, v.ParseFile(path))
+	assert.Equal(t, []string{"SNAPSHOT"}, v.PreRelease)
+	v.Release()
+	assert.NoError(t, v.Save(path))
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Replace(root, "1.2.3-SNAPSHOT", "1.2.3", 1), string(b))
+	b, err = os.ReadFile(filepath.Join(dir, "core", FileMavenPom))
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Replace(core, "1.2.3-SNAPSHOT", "1.2.3", 1), string(b))
+
+	v = New()
+	assert.NoError(t, v.SetPomTarget(PomParent))
+	assert.NoError(t, v.ParseFile(path))
+	assert.Equal(t, 7, v.Major)
+	v.BumpMajor()
+	assert.NoError(t, v.Save(path))
+	b, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "<version>8.0.0</version>\n  </parent>")
+	assert.Contains(t, string(b), "<version>1.2.3</version>\n  <modules>")
+
+	v = New()
+	err = v.ParseFile(filepath.Join(dir, "core", FileMavenPom))
+	assert.ErrorContains(t, err, "-pom-target=parent")
+
+	friendly := "<project>\n  <version>${revision}</version>\n  <properties>\n    <revision>2.0.0-rc.1</revision>\n" +
+		"  </properties>\n</project>\n"
+	path = filepath.Join(t.TempDir(), FileMavenPom)
+	assert.NoError(t, os.WriteFile(path, []byte(friendly), 0644))
+	v = New()
+	assert.NoError(t, v.ParseFile(path))
+	v.BumpRC()
+	assert.NoError(t, v.Save(path))
+	b, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Replace(friendly, "2.0.0-rc.1", "2.0.0-rc.2", 1), string(b))
+
+	assert.Error(t, New().SetPomTarget("module"))
+}
+
+// TestDockerfile verifies the LABEL, ARG and ENV handling of a Dockerfile across stages.
+func TestDockerfile(t *testing.T) {
+	content := "ARG VERSION=1.2.3\nFROM golang AS build\nARG GO_VERSION=1.22.0\nENV APP_VERSION 1.2.3\n\n" +
+		"FROM alpine\nLABEL org.opencontainers.image.title=\"app\" \\\n      org.opencontainers.image.version=\"v1.2.3\" \\\n" +
+		"      org.opencontainers.image.revision=$REVISION\nENV VERSION=$VERSION\n"
+	testCases := []struct {
+		name     string
+		key      string
+		check    string
+		expected string
+	}{
+		{
+			name:  "First",
+			check: "1.2.3",
+			expected: "ARG VERSION=1.3.0\nFROM golang AS build\nARG GO_VERSION=1.22.0\nENV APP_VERSION 1.3.0\n\n" +
+				"FROM alpine\nLABEL org.opencontainers.image.title=\"app\" \\\n      org.opencontainers.image.version=\"v1.3.0\" \\\n" +
+				"      org.opencontainers.image.revision=$REVISION\nENV VERSION=$VERSION\n",
+		},
+		{
+			name:  "Selected",
+			key:   "LABEL:org.opencontainers.image.version",
+			check: "v1.2.3",
+			expected: "ARG VERSION=1.3.0\nFROM golang AS build\nARG GO_VERSION=1.22.0\nENV APP_VERSION 1.3.0\n\n" +
+				"FROM alpine\nLABEL org.opencontainers.image.title=\"app\" \\\n      org.opencontainers.image.version=\"v1.3.0\" \\\n" +
+				"      org.opencontainers.image.revision=$REVISION\nENV VERSION=$VERSION\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileDockerfile)
+			assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			v := New()
+			assert.NoError(t, v.SetDockerKey(tc.key))
+			assert.NoError(t, v.ParseFile(path))
+			assert.Equal(t, tc.check, v.Format(!v.NoPrefix()))
+			v.BumpMinor()
+			assert.NoError(t, v.Save(path))
+			b, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(b))
+		})
+	}
+
+	assert.Error(t, New().SetDockerKey("RUN:VERSION"))
+	v := New()
+	assert.NoError(t, v.SetDockerKey("ARG:GO_VERSION"))
+	v.path = FileDockerfile
+	v.raw = []byte(content)
+	assert.NoError(t, v.Parse())
+	assert.Equal(t, 22, v.Minor)
+
+	commented := "# ARG VERSION=0.0.1 (old)\nFROM alpine\nRUN echo ARG VERSION=0.0.2 && echo MYENV VERSION=0.0.3\n" +
+		"ARG VERSION=1.2.3\nLABEL title=\"app\" \\\n# org.opencontainers.image.version=\"0.0.4\"\n      vendor=\"acme\"\n"
+	path := filepath.Join(t.TempDir(), FileDockerfile)
+	assert.NoError(t, os.WriteFile(path, []byte(commented), 0644))
+	v = New()
+	assert.NoError(t, v.ParseFile(path))
+	assert.Equal(t, "1.2.3", v.String())
+	v.BumpPatch()
+	assert.NoError(t, v.Save(path))
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Replace(commented, "VERSION=1.2.3", "VERSION=1.2.4", 1), string(b))
+
+	lowercase := "from alpine\narg VERSION=1.2.3\nlabel org.opencontainers.image.version=\"1.2.3\"\n"
+	assert.NoError(t, os.WriteFile(path, []byte(lowercase), 0644))
+	v = New()
+	assert.NoError(t, v.SetDockerKey("ARG:VERSION"))
+	assert.NoError(t, v.ParseFile(path))
+	assert.Equal(t, "1.2.3", v.String())
+	v.BumpMinor()
+	assert.NoError(t, v.Save(path))
+	b, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.ReplaceAll(lowercase, "1.2.3", "1.3.0"), string(b))
+}
+
+// TestMigrateGoModule verifies that the module path and imports move to the next major version.
+func TestMigrateGoModule(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":           "module example.com/foo\n\ngo 1.24\n",
+		"foo.go":           "package foo\n\nimport (\n\t\"fmt\"\n\n\tbar \"example.com/foo/bar\"\n)\n\nvar _ = fmt.Sprint(bar.X)\n",
+		"bar/bar.go":       "package bar\n\nimport _ \"example.com/foobar\"\n\nconst X = 1\n",
+		"cmd/main.go":      "package main\n\nimport \"example.com/foo\"\n\nfunc main() { foo.Run() }\n",
+		"nested/go.mod":    "module example.com/foo/nested\n",
+		"nested/nested.go": "package nested\n\nimport \"example.com/foo\"\n",
+		"use.go":           "package foo\n\nimport _ \"example.com/foo/nested/sub\"\n",
+		"vendor/v/v.go":    "package v\n\nimport \"example.com/foo\"\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	v0, err := Parse("v0.3.0")
+	assert.NoError(t, err)
+	_, err = MigrateGoModule(filepath.Join(dir, FileGoMod), v0, false)
+	assert.ErrorContains(t, err, "v1.0.0")
+	m, err := MigrateGoModule(filepath.Join(dir, FileGoMod), v0, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, m.OldMajor)
+	assert.NotEmpty(t, m.Warning)
+	m, err = MigrateGoModule(filepath.Join(dir, FileGoMod), nil, false)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, m.Warning, "no released version to tell v0 from v1")
+
+	v1, err := Parse("v1.4.0")
+	assert.NoError(t, err)
+	m, err = MigrateGoModule(filepath.Join(dir, FileGoMod), v1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/foo/v2", m.NewPath)
+	assert.Empty(t, m.Warning)
+	assert.Len(t, m.Files, 3)
+	b, err := os.ReadFile(filepath.Join(dir, "foo.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, files["foo.go"], string(b), "nothing is written before Write")
+	assert.NoError(t, m.Write())
+
+	expected := map[string]string{
+		"go.mod":           "module example.com/foo/v2\n\ngo 1.24\n",
+		"foo.go":           strings.Replace(files["foo.go"], "example.com/foo/bar", "example.com/foo/v2/bar", 1),
+		"bar/bar.go":       files["bar/bar.go"],
+		"cmd/main.go":      strings.Replace(files["cmd/main.go"], "example.com/foo", "example.com/foo/v2", 1),
+		"nested/nested.go": files["nested/nested.go"],
+		"use.go":           files["use.go"],
+		"vendor/v/v.go":    files["vendor/v/v.go"],
+	}
+	for name, content := range expected {
+		b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(b), name)
+	}
+
+	v1.BumpMajor()
+	m, err = MigrateGoModule(filepath.Join(dir, FileGoMod), v1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/foo/v3", m.NewPath)
+	assert.Equal(t, 3, m.NewMajor)
+	assert.Empty(t, m.Warning)
+}
+
+// TestGoModSources verifies the Go version sources and the toolchain validation of a go.mod.
+func TestGoModSources(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		content  string
+		expected string
+		invalid  bool
+	}{
+		{
+			name:     "Explicit",
+			source:   "1.24.5",
+			content:  "module example.com/go\n\ngo 1.23\n\nrequire example.com/dep v1.2.3 // go 1.0\n",
+			expected: "module example.com/go\n\ngo 1.24.5\n\nrequire example.com/dep v1.2.3 // go 1.0\n",
+		},
+		{
+			name:     "Toolchain",
+			source:   GoSourceToolchain,
+			content:  "module example.com/go\n\ngo 1.23\n\ntoolchain go1.24.2\n",
+			expected: "module example.com/go\n\ngo 1.24.2\n\ntoolchain go1.24.2\n",
+		},
+		{
+			name:    "Older toolchain",
+			source:  "go1.25.0",
+			content: "module example.com/go\n\ngo 1.23\n\ntoolchain go1.24.2\n",
+			invalid: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileGoMod)
+			assert.NoError(t, os.WriteFile(path, []byte(tc.content), 0644))
+			v := New()
+			assert.NoError(t, v.SetGoSource(tc.source))
+			assert.NoError(t, v.ParseFile(path))
+			assert.Equal(t, 23, v.Minor)
+			err := v.Save(path)
+			if tc.invalid {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			b, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(b))
+		})
+	}
+
+	assert.Error(t, New().SetGoSource("latest"))
+	v := New()
+	assert.NoError(t, v.SetGoSource(GoSourceLocal))
+	version, err := v.goVersion()
+	assert.NoError(t, err)
+	assert.Regexp(t, `^\d+\.\d+`, version)
+}
+
+// TestCargoToml verifies that only the package version changes when a Cargo.toml is saved.
+func TestCargoToml(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name: "Package",
+			content: "# my crate\n[package]\nname = \"crate\" # keep me\nversion   =   \"0.4.1-alpha.1\"\nedition = \"2021\"\n\n" +
+				"[dependencies]\nserde = { version = \"1.0.0\" }\n",
+			expected: "# my crate\n[package]\nname = \"crate\" # keep me\nversion   =   \"0.4.1-alpha.2\"\nedition = \"2021\"\n\n" +
+				"[dependencies]\nserde = { version = \"1.0.0\" }\n",
+		},
+		{
+			name:     "Workspace",
+			content:  "[workspace]\nmembers = [\n  \"a\",\n]\n\n[workspace.package]\nversion = '0.4.1-alpha.1'\n",
+			expected: "[workspace]\nmembers = [\n  \"a\",\n]\n\n[workspace.package]\nversion = '0.4.1-alpha.2'\n",
+		},
+		{
+			name:     "Inherited",
+			content:  "[package]\nname = \"a\"\nversion.workspace = true\n\n[workspace.package]\nversion = \"0.4.1-alpha.1\"\n",
+			expected: "[package]\nname = \"a\"\nversion.workspace = true\n\n[workspace.package]\nversion = \"0.4.1-alpha.2\"\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileCargoToml)
+			assert.NoError(t, os.WriteFile(path, []byte(tc.content), 0644))
+			v := New()
+			assert.NoError(t, v.ParseFile(path))
+			assert.Equal(t, 1, v.Alpha)
+			v.BumpAlpha()
+			assert.NoError(t, v.Save(path))
+			b, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(b))
+		})
+	}
+
+	v := New()
+	v.path = FileCargoToml
+	v.raw = []byte("[package]\nname = \"a\"\nversion.workspace = true\n")
+	assert.Error(t, v.Parse())
+}
+
+// TestPython verifies the pyproject.toml, setup.cfg and __version__ handlers and the PEP 440 spellings.
+func TestPython(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filename string
+		content  string
+		bumpFunc func(*Version)
+		expected string
+	}{
+		{
+			name:     "PEP 621",
+			filename: FilePyProject,
+			content:  "[build-system]\nrequires = [\"hatchling\"]\n\n[project]\nname = \"pkg\"\nversion = \"1.2.3rc1\" # release\n",
+			bumpFunc: (*Version).BumpRC,
+			expected: "[build-system]\nrequires = [\"hatchling\"]\n\n[project]\nname = \"pkg\"\nversion = \"1.2.3rc2\" # release\n",
+		},
+		{
+			name:     "Poetry",
+			filename: FilePyProject,
+			content:  "[tool.poetry]\nname = \"pkg\"\nversion = \"0.9.0\"\n",
+			bumpFunc: (*Version).BumpAlpha,
+			expected: "[tool.poetry]\nname = \"pkg\"\nversion = \"0.9.0a1\"\n",
+		},
+		{
+			name:     "setup.cfg",
+			filename: FileSetupCfg,
+			content:  "[metadata]\nname = pkg\nversion = 2.0.0b3\n\n[options]\nzip_safe = False\n",
+			bumpFunc: (*Version).BumpMinor,
+			expected: "[metadata]\nname = pkg\nversion = 2.1.0\n\n[options]\nzip_safe = False\n",
+		},
+		{
+			name:     "__init__.py",
+			filename: "__init__.py",
+			content:  "\"\"\"Package.\"\"\"\n\n__version__ = '1.0.0.dev4'\n__all__ = []\n",
+			bumpFunc: (*Version).BumpPatch,
+			expected: "\"\"\"Package.\"\"\"\n\n__version__ = '1.0.1'\n__all__ = []\n",
+		},
+		{
+			name:     "two release segments",
+			filename: FilePyProject,
+			content:  "[project]\nname = \"pkg\"\nversion = \"1.2b2\"\n",
+			bumpFunc: (*Version).BumpBeta,
+			expected: "[project]\nname = \"pkg\"\nversion = \"1.2b3\"\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.filename)
+			assert.NoError(t, os.WriteFile(path, []byte(tc.content), 0644))
+			v := New()
+			assert.NoError(t, v.ParseFile(path))
+			tc.bumpFunc(v)
+			assert.NoError(t, v.Save(path))
+			b, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(b))
+		})
+	}
+
+	spellings := map[string]string{
+		"1.2.3rc1":     "1.2.3rc1",
+		"1.2.3.RC_1":   "1.2.3rc1",
+		"1.2.3.alpha2": "1.2.3a2",
+		"1.2b":         "1.2b0",
+		"1.2.3c4":      "1.2.3rc4",
+		"1.2.3.dev4":   "1.2.3.dev4",
+		"1.2.3a1.dev2": "1.2.3a1.dev2",
+	}
+	for input, expected := range spellings {
+		v, err := Parse(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, v.String(), input)
+	}
+	v, err := Parse("1.2.3rc1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v.RC)
+}
+
+// TestGradleAndDotNet verifies the Gradle and .NET handlers and four part versions.
+func TestGradleAndDotNet(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filename string
+		content  string
+		bumpFunc func(*Version)
+		expected string
+	}{
+		{
+			name:     "gradle.properties",
+			filename: FileGradleProperties,
+			content:  "# project\ngroup=com.example\nversion=1.4.0\norg.gradle.jvmargs=-Xmx2g\n",
+			bumpFunc: (*Version).BumpMinor,
+			expected: "# project\ngroup=com.example\nversion=1.5.0\norg.gradle.jvmargs=-Xmx2g\n",
+		},
+		{
+			name:     "build.gradle",
+			filename: FileBuildGradle,
+			content:  "plugins {\n    id 'java'\n}\n\ngroup = 'com.example'\nversion = '1.4.0'\n",
+			bumpFunc: (*Version).BumpPatch,
+			expected: "plugins {\n    id 'java'\n}\n\ngroup = 'com.example'\nversion = '1.4.1'\n",
+		},
+		{
+			name:     "build.gradle.kts",
+			filename: FileBuildGradleKts,
+			content:  "group = \"com.example\"\nversion = \"2.0.0-rc.1\"\n",
+			bumpFunc: (*Version).BumpRC,
+			expected: "group = \"com.example\"\nversion = \"2.0.0-rc.2\"\n",
+		},
+		{
+			name:     "Version",
+			filename: "App.csproj",
+			content:  "<Project Sdk=\"Microsoft.NET.Sdk\">\n  <PropertyGroup>\n    <Version>1.2.3.4</Version>\n  </PropertyGroup>\n</Project>\n",
+			bumpFunc: (*Version).BumpRevision,
+			expected: "<Project Sdk=\"Microsoft.NET.Sdk\">\n  <PropertyGroup>\n    <Version>1.2.3.5</Version>\n  </PropertyGroup>\n</Project>\n",
+		},
+		{
+			name:     "PackageReference Version",
+			filename: "App.csproj",
+			content: "<Project Sdk=\"Microsoft.NET.Sdk\">\n  <ItemGroup>\n    <PackageReference Include=\"Newtonsoft.Json\">\n      <Version>13.0.1</Version>\n    </PackageReference>\n  </ItemGroup>\n" +
+				"  <PropertyGroup>\n    <Version>1.2.3</Version>\n  </PropertyGroup>\n</Project>\n",
+			bumpFunc: (*Version).BumpMinor,
+			expected: "<Project Sdk=\"Microsoft.NET.Sdk\">\n  <ItemGroup>\n    <PackageReference Include=\"Newtonsoft.Json\">\n      <Version>13.0.1</Version>\n    </PackageReference>\n  </ItemGroup>\n" +
+				"  <PropertyGroup>\n    <Version>1.3.0</Version>\n  </PropertyGroup>\n</Project>\n",
+		},
+		{
+			name:     "VersionPrefix with PackageReference Version",
+			filename: "App.csproj",
+			content:  "<Project>\n  <PropertyGroup>\n    <VersionPrefix>1.2.3</VersionPrefix>\n  </PropertyGroup>\n  <ItemGroup>\n    <PackageReference Include=\"Serilog\"><Version>3.1.1</Version></PackageReference>\n  </ItemGroup>\n</Project>\n",
+			bumpFunc: (*Version).BumpPatch,
+			expected: "<Project>\n  <PropertyGroup>\n    <VersionPrefix>1.2.4</VersionPrefix>\n  </PropertyGroup>\n  <ItemGroup>\n    <PackageReference Include=\"Serilog\"><Version>3.1.1</Version></PackageReference>\n  </ItemGroup>\n</Project>\n",
+		},
+		{
+			name:     "VersionPrefix",
+			filename: FileBuildProps,
+			content:  "<Project>\n  <PropertyGroup>\n    <VersionPrefix>1.2.3</VersionPrefix>\n  </PropertyGroup>\n</Project>\n",
+			bumpFunc: (*Version).BumpBeta,
+			expected: "<Project>\n  <PropertyGroup>\n    <VersionPrefix>1.2.3</VersionPrefix>\n    <VersionSuffix>beta.1</VersionSuffix>\n  </PropertyGroup>\n</Project>\n",
+		},
+		{
+			name:     "VersionSuffix",
+			filename: FileBuildProps,
+			content:  "<Project>\n  <PropertyGroup>\n    <VersionPrefix>1.2.3</VersionPrefix>\n    <VersionSuffix>beta.1</VersionSuffix>\n  </PropertyGroup>\n</Project>\n",
+			bumpFunc: (*Version).BumpMinor,
+			expected: "<Project>\n  <PropertyGroup>\n    <VersionPrefix>1.3.0</VersionPrefix>\n    <VersionSuffix></VersionSuffix>\n  </PropertyGroup>\n</Project>\n",
+		},
+		{
+			name:     "AssemblyInfo.cs",
+			filename: FileAssemblyInfo,
+			content: "using System.Reflection;\n\n[assembly: AssemblyVersion(\"1.0.0.0\")]\n[assembly: AssemblyFileVersion(\"1.0.0.0\")]\n" +
+				"[assembly: AssemblyInformationalVersion(\"1.0.0.0\")]\n",
+			bumpFunc: (*Version).BumpMajor,
+			expected: "using System.Reflection;\n\n[assembly: AssemblyVersion(\"2.0.0.0\")]\n[assembly: AssemblyFileVersion(\"2.0.0.0\")]\n" +
+				"[assembly: AssemblyInformationalVersion(\"2.0.0.0\")]\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.filename)
+			assert.NoError(t, os.WriteFile(path, []byte(tc.content), 0644))
+			v := New()
+			assert.NoError(t, v.ParseFile(path))
+			tc.bumpFunc(v)
+			assert.NoError(t, v.Save(path))
+			b, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(b))
+		})
+	}
+
+	for input, expected := range map[string]string{"1.2.3.4": "1.2.3.4", "v1.2.3.4": "v1.2.3.4"} {
+		v, err := Parse(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, 4, v.Revision, input)
+		assert.Equal(t, expected, v.String(), input)
+	}
+	a, _ := Parse("1.2.3.4")
+	b, _ := Parse("1.2.3.10")
+	assert.Equal(t, -1, a.Compare(b))
+}
+
 func FuzzParse(f *testing.F) {
 	testcases := []string{
 		"v1.2.3", "1.2.3", "1.21", "v1.2.3-alpha.1", "v1.2.3-beta.1", "v1.2.3-rc.1",
 		"v1.2.3-beta.1-alpha.1", "v1.2.3-preview.1", "vx.y.z", "v1.2.3-garbage", "", "v-1.-2.-3",
+		"v1.2.3-alpha.1.x7", "1.2.3-0.3.7", "v1.4.0+20261017.abc1234", "1.0.0-rc.1+build.1",
 	}
 	for _, tc := range testcases {
 		f.Add(tc)
@@ -262,6 +1071,408 @@ func FuzzParse(f *testing.F) {
 	})
 }
 
+// TestSaveAll verifies that Assign keeps several files in lockstep and that WriteFiles updates all of them or none.
+func TestSaveAll(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		FileVersion:     "v1.2.3",
+		FilePackageJson: "{\n  \"version\": \"1.2.3\"\n}\n",
+		FileHelmChart:   "name: app\nversion: 1.2.3 # chart\n",
+	}
+	var versions []*Version
+	for _, name := range []string{FileVersion, FilePackageJson, FileHelmChart} {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(files[name]), 0644))
+		v := New()
+		assert.NoError(t, v.ParseFile(path))
+		versions = append(versions, v)
+	}
+	versions[0].BumpMinor()
+	versions[0].BumpRC()
+	for _, v := range versions[1:] {
+		v.Assign(versions[0])
+	}
+	assert.Equal(t, "1.3.0-rc.1", versions[1].Format(!versions[1].NoPrefix()))
+	assert.NoError(t, SaveAll(versions...))
+	for name, expected := range map[string]string{
+		FileVersion:     "v1.3.0-rc.1",
+		FilePackageJson: "{\n  \"version\": \"1.3.0-rc.1\"\n}\n",
+		FileHelmChart:   "name: app\nversion: 1.3.0-rc.1 # chart\n",
+	} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(b))
+	}
+
+	err := WriteFiles(map[string][]byte{
+		filepath.Join(dir, FileVersion):            []byte("v2.0.0"),
+		filepath.Join(dir, "missing", FileVersion): []byte("v2.0.0"),
+	})
+	assert.Error(t, err)
+	b, err := os.ReadFile(filepath.Join(dir, FileVersion))
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.3.0-rc.1", string(b))
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3, "temporary files should be removed")
+
+	script := filepath.Join(dir, "release.sh")
+	assert.NoError(t, os.WriteFile(script, []byte("echo v2.0.0\n"), 0644))
+	err = restoreFiles([]string{script}, map[string]originalFile{script: {content: []byte("echo v1.3.0\n"), perm: 0700}})
+	assert.NoError(t, err)
+	b, err = os.ReadFile(script)
+	assert.NoError(t, err)
+	assert.Equal(t, "echo v1.3.0\n", string(b))
+	info, err := os.Stat(script)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm(), "rolled back files keep their permissions")
+
+	target := filepath.Join(dir, "shared", FileVersion)
+	link := filepath.Join(dir, "LINKED")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(target), 0755))
+	assert.NoError(t, os.WriteFile(target, []byte("v1.3.0"), 0644))
+	assert.NoError(t, os.Symlink(filepath.Join("shared", FileVersion), link))
+	assert.NoError(t, WriteFiles(map[string][]byte{link: []byte("v1.4.0")}))
+	linkInfo, err := os.Lstat(link)
+	assert.NoError(t, err)
+	assert.NotZero(t, linkInfo.Mode()&os.ModeSymlink, "the symlink is kept")
+	b, err = os.ReadFile(target)
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.4.0", string(b), "the target of the symlink is updated")
+}
+
+// TestFindVersionFiles verifies which files of a repository FindVersionFiles reports as version sources.
+func TestFindVersionFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		FileVersion:                           "v1.2.3",
+		FileGoMod:                             "module example.com/app\n\ngo 1.24\n",
+		filepath.Join("web", FilePackageJson): `{"version": "1.2.3"}`,
+		filepath.Join("web", "node_modules", "x", FilePackageJson): `{"version": "9.9.9"}`,
+		filepath.Join("charts", "app", FileHelmChart):              "name: app\nversion: 1.2.2\n",
+		filepath.Join(".git", FileVersion):                         "v0.0.1",
+		filepath.Join("src", "app", "__init__.py"):                 "__version__ = \"1.2.3\"\n",
+		filepath.Join("src", "app", "main.py"):                     "print('hello')\n",
+		filepath.Join("src", "App.csproj"):                         "<Project><PropertyGroup><Version>1.2.3</Version></PropertyGroup></Project>",
+		"README.md":                                                "# app\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	paths, err := FindVersionFiles(dir)
+	assert.NoError(t, err)
+	var found []string
+	for _, p := range paths {
+		rel, err := filepath.Rel(dir, p)
+		assert.NoError(t, err)
+		found = append(found, filepath.ToSlash(rel))
+	}
+	assert.Equal(t, []string{"VERSION", "charts/app/Chart.yaml", "src/App.csproj", "src/app/__init__.py", "web/package.json"}, found)
+}
+
+// TestWorkspace verifies the discovery of npm, Cargo, Go and Helm packages and the plan of a bump of their dependents.
+func TestWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		FilePackageJson: `{"private": true, "workspaces": ["packages/*"]}`,
+		filepath.Join("packages", "ui", FilePackageJson):  "{\n  \"name\": \"@acme/ui\",\n  \"version\": \"1.2.3\"\n}\n",
+		filepath.Join("packages", "web", FilePackageJson): "{\n  \"name\": \"web\",\n  \"dependencies\": {\"@acme/ui\": \"^1.2.3\"}\n}\n",
+		FileCargoToml: "[workspace]\nmembers = [\"crates/*\"]\n\n[workspace.package]\nversion = \"0.4.0\"\n",
+		filepath.Join("crates", "core", FileCargoToml): "[package]\nname = \"core\"\nversion.workspace = true\n",
+		filepath.Join("crates", "cli", FileCargoToml): "[package]\nname = \"cli\"\nversion = \"2.0.0\"\n\n" +
+			"[dependencies]\ncore = { path = \"../core\", version = \"=0.4.0\" }\n",
+		FileGoMod: "module example.com/mono\n\ngo 1.24\n\nrequire (\n\texample.com/mono/util v0.0.0\n)\n\n" +
+			"replace example.com/mono/util v0.0.0 => ./util\n",
+		filepath.Join("util", FileGoMod):                  "module example.com/mono/util\n\ngo 1.24\n",
+		filepath.Join("charts", "app", FileHelmChart):     "name: app\nversion: 0.3.0\ndependencies:\n  - name: db\n    version: \"~0.1.0\"\n    repository: file://../db\n",
+		filepath.Join("charts", "db", FileHelmChart):      "name: db\nversion: 0.1.0 # db\n",
+		filepath.Join("node_modules", "x", FileHelmChart): "name: x\nversion: 9.9.9\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	w, err := DiscoverWorkspace(dir)
+	assert.NoError(t, err)
+	var names []string
+	for _, p := range w.Packages {
+		names = append(names, p.Kind+":"+p.Name+"@"+p.Version)
+	}
+	assert.ElementsMatch(t, []string{"npm:@acme/ui@1.2.3", "npm:web@", "cargo:core@0.4.0", "cargo:cli@2.0.0",
+		"go:example.com/mono@v0.0.0", "go:example.com/mono/util@v0.0.0", "helm:app@0.3.0", "helm:db@0.1.0"}, names)
+
+	var packages []*Package
+	for _, name := range []string{"@acme/ui", filepath.Join(dir, "crates", "core"), "example.com/mono/util", "db"} {
+		p, err := w.Find(name)
+		assert.NoError(t, err, name)
+		packages = append(packages, p)
+	}
+	plan, err := w.Plan(packages, (*Version).BumpMinor)
+	assert.NoError(t, err)
+	assert.Len(t, plan.Packages, 4)
+	assert.Equal(t, "util/v0.1.0", plan.Packages[2].Tag)
+	assert.NoError(t, plan.Write())
+	for name, expected := range map[string]string{
+		filepath.Join("packages", "ui", FilePackageJson):  "{\n  \"name\": \"@acme/ui\",\n  \"version\": \"1.3.0\"\n}\n",
+		filepath.Join("packages", "web", FilePackageJson): "{\n  \"name\": \"web\",\n  \"dependencies\": {\"@acme/ui\": \"^1.3.0\"}\n}\n",
+		FileCargoToml: "[workspace]\nmembers = [\"crates/*\"]\n\n[workspace.package]\nversion = \"0.5.0\"\n",
+		filepath.Join("crates", "cli", FileCargoToml): "[package]\nname = \"cli\"\nversion = \"2.0.0\"\n\n" +
+			"[dependencies]\ncore = { path = \"../core\", version = \"=0.5.0\" }\n",
+		FileGoMod: "module example.com/mono\n\ngo 1.24\n\nrequire (\n\texample.com/mono/util v0.1.0\n)\n\n" +
+			"replace example.com/mono/util v0.0.0 => ./util\n",
+		filepath.Join("charts", "app", FileHelmChart): "name: app\nversion: 0.3.0\ndependencies:\n  - name: db\n    version: \"~0.2.0\"\n    repository: file://../db\n",
+		filepath.Join("charts", "db", FileHelmChart):  "name: db\nversion: 0.2.0 # db\n",
+	} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(b), name)
+	}
+
+	web, err := w.Find("web")
+	assert.NoError(t, err)
+	_, err = w.Plan([]*Package{web}, (*Version).BumpPatch)
+	assert.Error(t, err, "a package without a version cannot be bumped")
+
+	dir = t.TempDir()
+	for name, content := range map[string]string{
+		FilePackageJson: `{"private": true, "workspaces": ["packages/*"]}`,
+		filepath.Join("packages", "lib", FilePackageJson): `{"name": "lib", "version": "1.4.0"}`,
+		filepath.Join("packages", "app", FilePackageJson): `{"name": "app", "dependencies": {"lib": ">=1.0.0 <2.0.0"}}`,
+	} {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	w, err = DiscoverWorkspace(dir)
+	assert.NoError(t, err)
+	lib, err := w.Find("lib")
+	assert.NoError(t, err)
+	plan, err = w.Plan([]*Package{lib}, (*Version).BumpMinor)
+	assert.NoError(t, err)
+	assert.Empty(t, plan.References, "1.5.0 still satisfies the range")
+	_, err = w.Plan([]*Package{lib}, (*Version).BumpMajor)
+	assert.ErrorContains(t, err, ">=1.0.0 <2.0.0")
+}
+
+// TestGitTags verifies that GitLatestTag picks the highest version of the tags with a prefix reachable from HEAD, that
+// GitTag creates annotated and lightweight tags and that GitCommit commits the staged files.
+func TestGitTags(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"config", "user.name", "bump"},
+		{"config", "user.email", "bump@example.com"},
+		{"commit", "--quiet", "--allow-empty", "--message", "initial"},
+		{"tag", "v1.2.3"},
+		{"tag", "v1.10.0"},
+		{"tag", "v1.11.0-rc.1"},
+		{"tag", "v1.9.0"},
+		{"tag", "vnext"},
+		{"tag", "mylib/v2.0.0"},
+		{"checkout", "--quiet", "-b", "side"},
+		{"commit", "--quiet", "--allow-empty", "--message", "side"},
+		{"tag", "v3.0.0"},
+		{"checkout", "--quiet", "-"},
+	} {
+		_, err := git(dir, args...)
+		assert.NoError(t, err, args)
+	}
+
+	tag, v, err := GitLatestTag(dir, "v")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.11.0-rc.1", tag)
+	assert.Equal(t, "v1.11.0-rc.1", v.Format(true))
+
+	tag, v, err = GitLatestTag(dir, "mylib/v")
+	assert.NoError(t, err)
+	assert.Equal(t, "mylib/v2.0.0", tag)
+	assert.Equal(t, "v2.0.0", v.Format(true))
+
+	tag, v, err = GitLatestTag(dir, "other/")
+	assert.NoError(t, err)
+	assert.Empty(t, tag)
+	assert.Nil(t, v)
+
+	assert.NoError(t, GitTag(dir, "v1.11.0", "Release v1.11.0", false))
+	exists, err := GitTagExists(dir, "v1.11.0")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	kind, err := git(dir, "cat-file", "-t", "v1.11.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "tag", kind)
+	tag, _, err = GitLatestTag(dir, "v")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.11.0", tag)
+	assert.Error(t, GitTag(dir, "v1.11.0", "Release v1.11.0", false), "an existing tag cannot be created again")
+	assert.NoError(t, GitTag(dir, "v1.11.1", "", false))
+	kind, err = git(dir, "cat-file", "-t", "v1.11.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "commit", kind)
+	assert.Error(t, GitTag(dir, "v1.11.2", "", true), "a lightweight tag cannot be signed")
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, FileVersion), []byte("v1.11.2"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "NOTES"), []byte("notes"), 0644))
+	_, err = git(dir, "add", "NOTES")
+	assert.NoError(t, err)
+	staged, err := GitStaged(dir)
+	assert.NoError(t, err)
+	assert.Len(t, staged, 1)
+	assert.Equal(t, "NOTES", filepath.Base(staged[0]))
+	assert.True(t, filepath.IsAbs(staged[0]))
+	_, err = git(dir, "reset", "--quiet")
+	assert.NoError(t, err)
+	sha, err := GitCommit(dir, "chore(release): v1.11.1 → v1.11.2", []string{FileVersion}, false)
+	assert.NoError(t, err)
+	head, err := GitShortCommit(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, head, sha)
+	files, err := git(dir, "show", "--name-only", "--format=%s", "HEAD")
+	assert.NoError(t, err)
+	assert.Equal(t, "chore(release): v1.11.1 → v1.11.2\n\nVERSION", files)
+}
+
+// TestConventionalCommits verifies the classification of commit messages, the 0.x semantics of breaking changes and the
+// level implied by the commits since a tag.
+func TestConventionalCommits(t *testing.T) {
+	v1, v0 := &Version{Major: 1}, &Version{Minor: 4}
+	for message, expected := range map[string]Commit{
+		"feat(api): add the export command":                    {Type: "feat", Scope: "api", Level: LevelMinor},
+		"fix: handle an empty VERSION":                         {Type: "fix", Level: LevelPatch},
+		"Perf: cache the parsed forms":                         {Type: "perf", Level: LevelPatch},
+		"docs: explain -auto":                                  {Type: "docs", Level: LevelNone},
+		"feat!: drop -w":                                       {Type: "feat", Breaking: true, Level: LevelMajor},
+		"refactor: rename\n\nBREAKING CHANGE: -in is required": {Type: "refactor", Breaking: true, Level: LevelMajor},
+		"Merge branch 'main'":                                  {Level: LevelNone},
+		"feat:missing space":                                   {Level: LevelNone},
+	} {
+		c := ParseCommit("abc", message, nil, v1)
+		assert.Equal(t, expected.Type, c.Type, message)
+		assert.Equal(t, expected.Scope, c.Scope, message)
+		assert.Equal(t, expected.Breaking, c.Breaking, message)
+		assert.Equal(t, expected.Level, c.Level, message)
+	}
+	assert.Equal(t, LevelMinor, ParseCommit("abc", "feat!: drop -w", nil, v0).Level, "breaking changes bump minor before 1.0.0")
+	assert.Equal(t, LevelPatch, ParseCommit("abc", "docs: explain -auto", map[string]string{"docs": LevelPatch}, v1).Level)
+	assert.Equal(t, LevelNone, ParseCommit("abc", "docs: explain -auto", map[string]string{"docs": "huge"}, v1).Level)
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"config", "user.name", "bump"},
+		{"config", "user.email", "bump@example.com"},
+		{"commit", "--quiet", "--allow-empty", "--message", "feat!: initial"},
+		{"tag", "v1.0.0"},
+		{"commit", "--quiet", "--allow-empty", "--message", "fix: first"},
+		{"commit", "--quiet", "--allow-empty", "--message", "chore: tidy"},
+		{"commit", "--quiet", "--allow-empty", "--message", "feat(cli): second\n\nwith a body"},
+	} {
+		_, err := git(dir, args...)
+		assert.NoError(t, err, args)
+	}
+	commits, err := GitCommits(dir, "v1.0.0", nil, v1)
+	assert.NoError(t, err)
+	assert.Len(t, commits, 3)
+	level, drivers := CommitLevel(commits)
+	assert.Equal(t, LevelMinor, level)
+	assert.Len(t, drivers, 1)
+	assert.Equal(t, "feat(cli): second", drivers[0].Subject)
+	assert.Len(t, drivers[0].Hash, 40)
+
+	commits, err = GitCommits(dir, "", nil, v1)
+	assert.NoError(t, err)
+	level, _ = CommitLevel(commits)
+	assert.Equal(t, LevelMajor, level)
+
+	level, drivers = CommitLevel(commits[1:2])
+	assert.Equal(t, LevelNone, level)
+	assert.Empty(t, drivers)
+}
+
+// TestChangelog verifies the generated changelog section and the promotion of the [Unreleased] section of a Keep a
+// Changelog file with its compare links.
+func TestChangelog(t *testing.T) {
+	date := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	v := &Version{Major: 1}
+	var commits []Commit
+	for i, message := range []string{"feat(cli): add -auto", "fix: crash on empty VERSION", "chore: tidy", "refactor!: drop -w"} {
+		commits = append(commits, ParseCommit(fmt.Sprintf("%040d", i), message, nil, v))
+	}
+	r := Release{Version: "1.3.0", Tag: "v1.3.0", Date: date, Commits: commits}
+	assert.Equal(t, "## [1.3.0] - 2026-10-17\n\n### Breaking Changes\n\n- drop -w (0000000)\n\n"+
+		"### Features\n\n- **cli:** add -auto (0000000)\n\n### Bug Fixes\n\n- crash on empty VERSION (0000000)\n\n",
+		ChangelogSection(r))
+
+	links := "[Unreleased]: https://github.com/o/r/compare/v1.2.3...HEAD\n[1.2.3]: https://github.com/o/r/compare/v1.2.2...v1.2.3\n"
+	content := "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- Written by hand\n\n## [1.2.3] - 2026-01-02\n\n- Old\n\n" + links
+	promoted, section, err := PromoteChangelog([]byte(content), r)
+	assert.NoError(t, err)
+	assert.Equal(t, "## [1.3.0] - 2026-10-17\n\n### Added\n\n- Written by hand\n", section)
+	assert.Equal(t, "# Changelog\n\n## [Unreleased]\n\n## [1.3.0] - 2026-10-17\n\n### Added\n\n- Written by hand\n\n"+
+		"## [1.2.3] - 2026-01-02\n\n- Old\n\n"+
+		"[Unreleased]: https://github.com/o/r/compare/v1.3.0...HEAD\n[1.3.0]: https://github.com/o/r/compare/v1.2.3...v1.3.0\n"+
+		"[1.2.3]: https://github.com/o/r/compare/v1.2.2...v1.2.3\n", string(promoted))
+
+	_, _, err = PromoteChangelog(promoted, r)
+	assert.Error(t, err, "a version cannot be promoted twice")
+
+	promoted, _, err = PromoteChangelog([]byte("# Changelog\n\n## [Unreleased]\n\n"+links), r)
+	assert.NoError(t, err)
+	assert.Contains(t, string(promoted), "## [Unreleased]\n\n## [1.3.0] - 2026-10-17\n\n### Breaking Changes\n\n- drop -w (0000000)\n")
+	assert.Contains(t, string(promoted), "- crash on empty VERSION (0000000)\n\n[Unreleased]: ")
+
+	promoted, _, err = PromoteChangelog(nil, Release{Version: "0.1.0", Date: date, Commits: commits[1:2]})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(promoted), "# Changelog\n"))
+	assert.True(t, strings.HasSuffix(string(promoted), "## [Unreleased]\n\n## [0.1.0] - 2026-10-17\n\n"+
+		"### Bug Fixes\n\n- crash on empty VERSION (0000000)\n"))
+}
+
+// TestChannels verifies that Promote walks the Ladder up to the final release and that SetChannel refuses to move back.
+func TestChannels(t *testing.T) {
+	v, err := Parse("v1.2.3-alpha.4")
+	assert.NoError(t, err)
+	assert.Equal(t, "alpha", v.Channel())
+	for _, expected := range []string{"v1.2.3-beta.1", "v1.2.3-rc.1", "v1.2.3"} {
+		assert.NoError(t, v.Promote())
+		assert.Equal(t, expected, v.String())
+	}
+	assert.Empty(t, v.Channel())
+	assert.Error(t, v.Promote(), "a final release cannot be promoted")
+	assert.Error(t, v.SetChannel("rc"), "a final release cannot move back to a channel")
+
+	v, err = Parse("1.2.3-beta.2+build.5")
+	assert.NoError(t, err)
+	assert.NoError(t, v.SetChannel("beta"))
+	assert.Equal(t, "1.2.3-beta.2+build.5", v.Format(true), "the current channel is kept as it is")
+	assert.NoError(t, v.SetChannel("rc"))
+	assert.Equal(t, "1.2.3-rc.1", v.Format(true))
+	assert.Error(t, v.SetChannel("alpha"), "rc cannot move back to alpha")
+	assert.Error(t, v.SetChannel("nightly"), "nightly is not on the ladder")
+
+	v, err = Parse("v1.2.3-SNAPSHOT")
+	assert.NoError(t, err)
+	assert.Error(t, v.Promote(), "SNAPSHOT is not on the ladder")
+	assert.NoError(t, v.SetChannel("alpha"))
+	assert.Equal(t, "v1.2.3-alpha.1", v.String())
+
+	defer func(ladder []string) { Ladder = ladder }(Ladder)
+	Ladder = []string{"dev", "rc"}
+	v, err = Parse("v2.0.0-dev.7")
+	assert.NoError(t, err)
+	assert.NoError(t, v.Promote())
+	assert.Equal(t, "v2.0.0-rc.1", v.String())
+	assert.Error(t, v.SetChannel("dev"))
+}
+
 func BenchmarkScan(b *testing.B) {
 	v := New()
 	rawVersion := []byte("v1.2.3-beta.4-alpha.5")
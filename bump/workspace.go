@@ -0,0 +1,705 @@
+package bump
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// Version inside a version requirement such as ^1.2.3, ~> 1.2, >=1.2.3 or workspace:^1.2.3
+	reRequirementVersion = regexp.MustCompile(`v?\d+(?:\.\d+){0,2}(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?`)
+	// Quoted strings of a TOML array such as members = ["crates/*", "cli"]
+	reTomlArrayString = regexp.MustCompile(`"([^"\\]*)"|'([^']*)'`)
+	// Cargo inline dependency table such as foo = { path = "../foo", version = "1.2.3" }
+	reCargoInlineVersion = regexp.MustCompile(`\bversion\s*=\s*"([^"\\]*)"`)
+)
+
+// npmDependencies are the keys of a package.json whose members reference other packages
+var npmDependencies = []string{"dependencies", "devDependencies", "peerDependencies", "optionalDependencies"}
+
+// cargoDependencies are the tables of a Cargo.toml whose keys reference other crates
+var cargoDependencies = []string{"dependencies", "dev-dependencies", "build-dependencies", "workspace.dependencies"}
+
+// Workspace is the package graph of a monorepo, made of the npm, Cargo, Go and Helm packages found by DiscoverWorkspace
+type Workspace struct {
+	Root     string     `json:"root"`
+	Packages []*Package `json:"packages"`
+
+	refs []reference // references between the packages of the Workspace
+}
+
+// Package is a package of a Workspace with its own version
+type Package struct {
+	Name         string   `json:"name"`
+	Kind         string   `json:"kind"`    // one of WorkspaceNpm, WorkspaceCargo, WorkspaceGo or WorkspaceHelm
+	Dir          string   `json:"dir"`     // directory of the package, relative to the root of the Workspace
+	File         string   `json:"file"`    // manifest of the package, such as packages/ui/package.json
+	Version      string   `json:"version"` // empty when the package has no version, such as a private npm app
+	Dependencies []string `json:"dependencies,omitempty"`
+
+	versionFile string   // file that holds the version, which is the root Cargo.toml for an inherited version
+	versionSpan tomlSpan // location of the version inside versionFile, unused for WorkspaceGo
+}
+
+// reference is the version requirement of a dependency on a package of the Workspace
+type reference struct {
+	from  string   // name of the package, or file of the manifest, that depends on the package
+	to    *Package // package that is depended on
+	file  string
+	span  tomlSpan // location of the requirement inside file, such as ^1.2.3
+	value string
+}
+
+// WorkspacePlan is the new version of the bumped packages of a Workspace and of every requirement that references them;
+// nothing is written until Write is called
+type WorkspacePlan struct {
+	Packages   []PackageChange   `json:"packages"`
+	References []ReferenceChange `json:"references,omitempty"`
+	Files      []string          `json:"files"` // every file that Write saves, relative to the root of the Workspace
+
+	root    string
+	changes map[string][]spanChange
+}
+
+// PackageChange is the version of a package of a WorkspacePlan before and after the bump
+type PackageChange struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	File string `json:"file"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+	Tag  string `json:"tag"` // git tag of the new version, see Package.TagPrefix
+}
+
+// ReferenceChange is a requirement on a bumped package of a WorkspacePlan before and after the bump
+type ReferenceChange struct {
+	File       string `json:"file"`
+	From       string `json:"from"`
+	Dependency string `json:"dependency"`
+	Old        string `json:"old"`
+	New        string `json:"new"`
+}
+
+// DiscoverWorkspace finds the packages of the monorepo at root: the members of the "workspaces" of a package.json (or
+// the packages of a pnpm-workspace.yaml), the members of the [workspace] of a Cargo.toml, every go.mod (versioned by
+// DIR/vX.Y.Z git tags) and every Chart.yaml. Hidden directories, vendor, node_modules and testdata are skipped.
+//
+// Example:
+// 		w, err := bump.DiscoverWorkspace(".")
+// 		ui, err := w.Find("@acme/ui")
+// 		plan, err := w.Plan([]*bump.Package{ui}, (*bump.Version).BumpMinor)
+// 		err = plan.Write()
+func DiscoverWorkspace(root string) (*Workspace, error) {
+	w := &Workspace{Root: root}
+	for _, discover := range []func() error{w.discoverNpm, w.discoverCargo, w.discoverGo, w.discoverHelm} {
+		if err := discover(); err != nil {
+			return nil, err
+		}
+	}
+	if len(w.Packages) == 0 {
+		return nil, fmt.Errorf("no npm, Cargo, Go or Helm packages found in %s", root)
+	}
+	for _, r := range w.refs {
+		for _, p := range w.Packages {
+			if (p.Name == r.from || p.File == r.from) && !slices.Contains(p.Dependencies, r.to.Name) {
+				p.Dependencies = append(p.Dependencies, r.to.Name)
+			}
+		}
+	}
+	return w, nil
+}
+
+// Find returns the package of the Workspace with the name, directory or manifest file of nameOrPath
+func (w *Workspace) Find(nameOrPath string) (*Package, error) {
+	var found []*Package
+	for _, p := range w.Packages {
+		if p.Name == nameOrPath || w.samePath(p.Dir, nameOrPath) || w.samePath(p.File, nameOrPath) {
+			found = append(found, p)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no package %q in workspace %s", nameOrPath, w.Root)
+	case 1:
+		return found[0], nil
+	}
+	files := make([]string, 0, len(found))
+	for _, p := range found {
+		files = append(files, p.File)
+	}
+	return nil, fmt.Errorf("package %q is ambiguous, use one of %s", nameOrPath, strings.Join(files, ", "))
+}
+
+// samePath reports whether the path relative to the root of the Workspace refers to the same file as other, which is
+// relative to the working directory or to the root
+func (w *Workspace) samePath(path, other string) bool {
+	a, errA := filepath.Abs(filepath.Join(w.Root, path))
+	b, errB := filepath.Abs(other)
+	c, errC := filepath.Abs(filepath.Join(w.Root, other))
+	return errA == nil && ((errB == nil && a == b) || (errC == nil && a == c))
+}
+
+// TagPrefix returns the prefix of the git tags of the package followed by its version: DIR/ for a Go module in DIR
+// (nothing for the root module), as the Go toolchain expects, and NAME@ for every other package
+func (p *Package) TagPrefix() string {
+	if p.Kind == WorkspaceGo {
+		if p.Dir == "." {
+			return ""
+		}
+		return filepath.ToSlash(p.Dir) + "/"
+	}
+	return p.Name + "@"
+}
+
+// LastTag returns the git tag of the highest version of the package, or an empty string when it has never been tagged
+func (w *Workspace) LastTag(p *Package) (string, error) {
+	out, err := git(w.Root, "tag", "--list", p.TagPrefix()+"*")
+	if err != nil {
+		return "", err
+	}
+	var (
+		tag     string
+		highest *Version
+	)
+	for _, t := range strings.Fields(out) {
+		v, err := Parse(strings.TrimPrefix(t, p.TagPrefix()))
+		if err != nil {
+			continue
+		}
+		if highest == nil || v.Compare(highest) > 0 {
+			tag, highest = t, v
+		}
+	}
+	return tag, nil
+}
+
+// Changed reports whether the files of the package differ from its LastTag, ignoring the packages nested inside of it;
+// a package that has never been tagged has changed
+func (w *Workspace) Changed(p *Package) (bool, error) {
+	tag, err := w.LastTag(p)
+	if err != nil || len(tag) == 0 {
+		return err == nil, err
+	}
+	args := []string{"diff", "--name-only", tag, "--", filepath.ToSlash(p.Dir)}
+	for _, other := range w.Packages {
+		if other.Dir != p.Dir && (p.Dir == "." || strings.HasPrefix(other.Dir, p.Dir+string(filepath.Separator))) {
+			args = append(args, ":(exclude)"+filepath.ToSlash(other.Dir))
+		}
+	}
+	out, err := git(w.Root, args...)
+	if err != nil {
+		return false, err
+	}
+	return len(out) > 0, nil
+}
+
+// Plan applies bump to the version of every package and updates each requirement on them inside the other packages
+// of the Workspace, keeping the operator of the requirement (^1.2.3 becomes ^1.3.0); a range of several comparators
+// (>=1.0.0 <2.0.0) is kept while the new version satisfies it and refused otherwise, since it has no single version to
+// rewrite
+func (w *Workspace) Plan(packages []*Package, bump func(*Version)) (*WorkspacePlan, error) {
+	plan := &WorkspacePlan{root: w.Root, changes: make(map[string][]spanChange)}
+	bumped := make(map[*Package]*Version)
+	for _, p := range packages {
+		if _, ok := bumped[p]; ok {
+			continue
+		}
+		if len(p.Version) == 0 {
+			return nil, fmt.Errorf("package %s in %s has no version to bump", p.Name, p.File)
+		}
+		v, err := Parse(p.Version)
+		if err != nil {
+			return nil, fmt.Errorf("package %s: %w", p.Name, err)
+		}
+		old := v.Major
+		bump(v)
+		v.useForm = "" // render the pre-release identifiers without the "v" of their Form, as savePackageJson does
+		newVersion := v.format(!v.noPrefix)
+		if p.Kind == WorkspaceGo {
+			newVersion = v.format(true)
+			if v.Major > 1 && v.Major != old {
+				return nil, fmt.Errorf("module %s changes its path at v%d, use -go-module to migrate it", p.Name, v.Major)
+			}
+		} else if err := plan.add(p.versionFile, spanChange{span: p.versionSpan, value: newVersion}); err != nil {
+			return nil, err
+		}
+		bumped[p] = v
+		plan.Packages = append(plan.Packages, PackageChange{
+			Name: p.Name, Kind: p.Kind, File: p.File, Old: p.Version, New: newVersion, Tag: p.TagPrefix() + newVersion,
+		})
+	}
+	for _, r := range w.refs {
+		v, ok := bumped[r.to]
+		if !ok {
+			continue
+		}
+		locs := reRequirementVersion.FindAllStringIndex(r.value, -1)
+		if len(locs) == 0 {
+			continue // such as workspace:* which is resolved when the package is published
+		}
+		if len(locs) > 1 {
+			if c, err := ParseConstraint(r.value); err == nil && c.Satisfies(v) {
+				continue
+			}
+			return nil, fmt.Errorf("%s requires %s %q, which %s does not satisfy, update the range by hand", r.file,
+				r.to.Name, r.value, v.format(false))
+		}
+		loc := locs[0]
+		newVersion := v.format(false)
+		if strings.HasPrefix(r.value[loc[0]:loc[1]], "v") {
+			newVersion = "v" + newVersion
+		}
+		value := r.value[:loc[0]] + newVersion + r.value[loc[1]:]
+		if value == r.value {
+			continue
+		}
+		if err := plan.add(r.file, spanChange{span: r.span, value: value}); err != nil {
+			return nil, err
+		}
+		plan.References = append(plan.References, ReferenceChange{
+			File: r.file, From: r.from, Dependency: r.to.Name, Old: r.value, New: value,
+		})
+	}
+	return plan, nil
+}
+
+// add records the change of the file at path relative to the root, refusing two different values for the same span,
+// which happens when packages that inherit one version are bumped differently
+func (plan *WorkspacePlan) add(path string, change spanChange) error {
+	for _, c := range plan.changes[path] {
+		if c.span == change.span {
+			if c.value != change.value {
+				return fmt.Errorf("%s cannot be set to both %s and %s", path, c.value, change.value)
+			}
+			return nil
+		}
+	}
+	if _, ok := plan.changes[path]; !ok {
+		plan.Files = append(plan.Files, path)
+	}
+	plan.changes[path] = append(plan.changes[path], change)
+	return nil
+}
+
+// Write saves every file of the plan using WriteFiles, so that either every file is updated or none is
+func (plan *WorkspacePlan) Write() error {
+	files := make(map[string][]byte, len(plan.changes))
+	for path, changes := range plan.changes {
+		full := filepath.Join(plan.root, path)
+		content, err := os.ReadFile(full)
+		if err != nil {
+			return err
+		}
+		files[full] = replaceSpans(content, slices.Clone(changes)...)
+	}
+	return WriteFiles(files)
+}
+
+// walk calls fn with the path relative to the root of every file named name inside the Workspace, skipping hidden
+// directories, vendor, node_modules and testdata
+func (w *Workspace) walk(name string, fn func(rel string) error) error {
+	return filepath.WalkDir(w.Root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			n := d.Name()
+			if p != w.Root && (strings.HasPrefix(n, ".") || n == "vendor" || n == "node_modules" || n == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != name {
+			return nil
+		}
+		rel, err := filepath.Rel(w.Root, p)
+		if err != nil {
+			return err
+		}
+		return fn(rel)
+	})
+}
+
+// members expands the directory patterns of a workspace (such as packages/* or crates/**) relative to the root into
+// the directories that contain name, where patterns starting with ! are excluded
+func (w *Workspace) members(patterns []string, name string) ([]string, error) {
+	var include, exclude []string
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			exclude = append(exclude, filepath.Clean(strings.TrimPrefix(pattern, "!")))
+		} else {
+			include = append(include, filepath.Clean(pattern))
+		}
+	}
+	var dirs []string
+	err := w.walk(name, func(rel string) error {
+		dir := filepath.Dir(rel)
+		if slices.ContainsFunc(include, func(p string) bool { return matchDir(p, dir) }) &&
+			!slices.ContainsFunc(exclude, func(p string) bool { return matchDir(p, dir) }) {
+			dirs = append(dirs, dir)
+		}
+		return nil
+	})
+	return dirs, err
+}
+
+// matchDir reports whether dir matches the workspace pattern, where ** matches any number of directories
+func matchDir(pattern, dir string) bool {
+	if before, _, ok := strings.Cut(pattern, "**"); ok {
+		return before == "" || strings.HasPrefix(dir+string(filepath.Separator), before)
+	}
+	ok, err := filepath.Match(pattern, dir)
+	return err == nil && ok
+}
+
+// discoverNpm adds the members of the "workspaces" of the root package.json, or the packages of pnpm-workspace.yaml,
+// and the requirements of their dependencies on each other
+func (w *Workspace) discoverNpm() error {
+	var patterns []string
+	if content, err := os.ReadFile(filepath.Join(w.Root, FilePackageJson)); err == nil {
+		var manifest struct {
+			Workspaces json.RawMessage `json:"workspaces"`
+		}
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return fmt.Errorf("invalid %s: %w", FilePackageJson, err)
+		}
+		var nested struct {
+			Packages []string `json:"packages"`
+		}
+		if json.Unmarshal(manifest.Workspaces, &patterns) != nil && json.Unmarshal(manifest.Workspaces, &nested) == nil {
+			patterns = nested.Packages
+		}
+	}
+	if content, err := os.ReadFile(filepath.Join(w.Root, "pnpm-workspace.yaml")); err == nil {
+		var manifest struct {
+			Packages []string `yaml:"packages"`
+		}
+		if err := yaml.Unmarshal(content, &manifest); err != nil {
+			return fmt.Errorf("invalid pnpm-workspace.yaml: %w", err)
+		}
+		patterns = append(patterns, manifest.Packages...)
+	}
+	if len(patterns) == 0 {
+		return nil
+	}
+	dirs, err := w.members(patterns, FilePackageJson)
+	if err != nil {
+		return err
+	}
+	contents := make(map[string][]byte)
+	var packages []*Package
+	for _, dir := range dirs {
+		file := filepath.Join(dir, FilePackageJson)
+		content, err := os.ReadFile(filepath.Join(w.Root, file))
+		if err != nil {
+			return err
+		}
+		var manifest struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		}
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return fmt.Errorf("invalid %s: %w", file, err)
+		}
+		if len(manifest.Name) == 0 {
+			continue
+		}
+		p := &Package{Name: manifest.Name, Kind: WorkspaceNpm, Dir: dir, File: file, Version: manifest.Version, versionFile: file}
+		if len(p.Version) > 0 {
+			if p.versionSpan, err = jsonValue(content, "version"); err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+		}
+		contents[file] = content
+		packages = append(packages, p)
+	}
+	for _, p := range packages {
+		for _, key := range npmDependencies {
+			members, err := jsonMembers(contents[p.File], key)
+			if err != nil {
+				return fmt.Errorf("%s: %w", p.File, err)
+			}
+			for _, to := range packages {
+				if span, ok := members[to.Name]; ok && to != p {
+					w.addRef(p.Name, to, p.File, contents[p.File], span)
+				}
+			}
+		}
+	}
+	w.Packages = append(w.Packages, packages...)
+	return nil
+}
+
+// discoverCargo adds the members of the [workspace] of the root Cargo.toml and the requirements of their dependencies
+// on each other, including those of the [workspace.dependencies] of the root
+func (w *Workspace) discoverCargo() error {
+	rootFile := FileCargoToml
+	root, err := os.ReadFile(filepath.Join(w.Root, rootFile))
+	if err != nil {
+		return nil
+	}
+	patterns, ok := tomlArray(root, "workspace", "members")
+	if !ok {
+		return nil
+	}
+	if excluded, ok := tomlArray(root, "workspace", "exclude"); ok {
+		for _, e := range excluded {
+			patterns = append(patterns, "!"+e)
+		}
+	}
+	dirs, err := w.members(patterns, FileCargoToml)
+	if err != nil {
+		return err
+	}
+	if _, ok := tomlValue(root, "package", "name"); ok && !slices.Contains(dirs, ".") {
+		dirs = append([]string{"."}, dirs...)
+	}
+	contents := map[string][]byte{rootFile: root}
+	var packages []*Package
+	for _, dir := range dirs {
+		file := filepath.Join(dir, FileCargoToml)
+		content, err := os.ReadFile(filepath.Join(w.Root, file))
+		if err != nil {
+			return err
+		}
+		nameSpan, ok := tomlValue(content, "package", "name")
+		if !ok {
+			continue
+		}
+		p := &Package{Name: string(content[nameSpan.start:nameSpan.end]), Kind: WorkspaceCargo, Dir: dir, File: file}
+		switch {
+		case tomlHasKey(content, "package", "version.workspace"):
+			if span, ok := tomlValue(root, "workspace.package", "version"); ok {
+				p.Version, p.versionFile, p.versionSpan = string(root[span.start:span.end]), rootFile, span
+			}
+		default:
+			if span, ok := tomlValue(content, "package", "version"); ok {
+				p.Version, p.versionFile, p.versionSpan = string(content[span.start:span.end]), file, span
+			}
+		}
+		contents[file] = content
+		packages = append(packages, p)
+	}
+	for _, file := range slices.Sorted(maps.Keys(contents)) {
+		content, from := contents[file], file
+		for _, p := range packages {
+			if p.File == file {
+				from = p.Name
+			}
+		}
+		for _, to := range packages {
+			if to.Name == from {
+				continue
+			}
+			for _, section := range cargoDependencies {
+				if span, ok := cargoRequirement(content, section, to.Name); ok {
+					w.addRef(from, to, file, content, span)
+				}
+			}
+		}
+	}
+	w.Packages = append(w.Packages, packages...)
+	return nil
+}
+
+// cargoRequirement locates the version requirement of the dependency name inside the section of a Cargo.toml, which is
+// name = "1.2", name = { version = "1.2", path = "..." } or a [section.name] table with a version key
+func cargoRequirement(content []byte, section, name string) (tomlSpan, bool) {
+	if span, ok := tomlValue(content, section, name); ok {
+		return span, true
+	}
+	re := regexp.MustCompile(`^\s*` + regexp.QuoteMeta(name) + `\s*=\s*\{`)
+	span, ok := tableValue(content, section, func(line []byte) (int, int, bool) {
+		if !re.Match(line) {
+			return 0, 0, false
+		}
+		m := reCargoInlineVersion.FindSubmatchIndex(line)
+		if m == nil {
+			return 0, 0, false
+		}
+		return m[2], m[3], true
+	})
+	if ok {
+		return span, true
+	}
+	return tomlValue(content, section+"."+name, "version")
+}
+
+// tomlArray returns the strings of the array value of key inside the [section] table of a TOML document, which may span
+// several lines
+func tomlArray(content []byte, section, key string) ([]string, bool) {
+	re := regexp.MustCompile(`^\s*` + regexp.QuoteMeta(key) + `\s*=\s*\[`)
+	span, ok := tableValue(content, section, func(line []byte) (int, int, bool) {
+		loc := re.FindIndex(line)
+		if loc == nil {
+			return 0, 0, false
+		}
+		return loc[1], loc[1], true
+	})
+	if !ok {
+		return nil, false
+	}
+	end := bytes.IndexByte(content[span.start:], ']')
+	if end < 0 {
+		return nil, false
+	}
+	var values []string
+	for _, line := range strings.Split(string(content[span.start:span.start+end]), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		for _, m := range reTomlArrayString.FindAllStringSubmatch(line, -1) {
+			values = append(values, m[1]+m[2])
+		}
+	}
+	return values, true
+}
+
+// discoverGo adds every go.mod of the Workspace, versioned by the git tags of its directory, and the require
+// directives of each module on the others
+func (w *Workspace) discoverGo() error {
+	contents := make(map[string][]byte)
+	var packages []*Package
+	err := w.walk(FileGoMod, func(file string) error {
+		content, err := os.ReadFile(filepath.Join(w.Root, file))
+		if err != nil {
+			return err
+		}
+		path, _, err := GoModulePath(content)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		p := &Package{Name: path, Kind: WorkspaceGo, Dir: filepath.Dir(file), File: file, Version: "v0.0.0"}
+		if tag, err := w.LastTag(p); err == nil && len(tag) > 0 {
+			p.Version = strings.TrimPrefix(tag, p.TagPrefix())
+		}
+		contents[file] = content
+		packages = append(packages, p)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, p := range packages {
+		requires := goRequires(contents[p.File])
+		for _, to := range packages {
+			if span, ok := requires[to.Name]; ok && to != p {
+				w.addRef(p.Name, to, p.File, contents[p.File], span)
+			}
+		}
+	}
+	w.Packages = append(w.Packages, packages...)
+	return nil
+}
+
+// goRequires locates the version of every require directive of a go.mod, keyed by module path, skipping the replace
+// and exclude directives
+func goRequires(content []byte) map[string]tomlSpan {
+	requires := make(map[string]tomlSpan)
+	inBlock, offset := false, 0
+	for _, line := range bytes.SplitAfter(content, []byte("\n")) {
+		lineStart := offset
+		offset += len(line)
+		text := string(line)
+		if i := strings.Index(text, "//"); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inBlock && fields[0] == ")":
+			inBlock = false
+			continue
+		case fields[0] == "require" && len(fields) > 1 && fields[1] == "(":
+			inBlock = true
+			continue
+		case fields[0] == "require":
+			fields = fields[1:]
+		case !inBlock:
+			continue
+		}
+		if len(fields) < 2 {
+			continue
+		}
+		path := strings.Trim(fields[0], `"`)
+		at := strings.Index(text, fields[0]) + len(fields[0])
+		start := at + strings.Index(text[at:], fields[1])
+		requires[path] = tomlSpan{start: lineStart + start, end: lineStart + start + len(fields[1])}
+	}
+	return requires
+}
+
+// discoverHelm adds every Chart.yaml of the Workspace and the file:// dependencies of the charts on each other
+func (w *Workspace) discoverHelm() error {
+	contents := make(map[string][]byte)
+	var packages []*Package
+	err := w.walk(FileHelmChart, func(file string) error {
+		content, err := os.ReadFile(filepath.Join(w.Root, file))
+		if err != nil {
+			return err
+		}
+		var chart struct {
+			Name string `yaml:"name"`
+		}
+		if err := yaml.Unmarshal(content, &chart); err != nil {
+			return fmt.Errorf("invalid %s: %w", file, err)
+		}
+		if len(chart.Name) == 0 {
+			return nil
+		}
+		p := &Package{Name: chart.Name, Kind: WorkspaceHelm, Dir: filepath.Dir(file), File: file, versionFile: file}
+		if span, node, err := yamlScalar(content, "version"); err == nil {
+			p.Version, p.versionSpan = node.Value, span
+		}
+		contents[file] = content
+		packages = append(packages, p)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, p := range packages {
+		content := contents[p.File]
+		var doc yaml.Node
+		if err := yaml.Unmarshal(content, &doc); err != nil || len(doc.Content) == 0 {
+			continue
+		}
+		deps := yamlMappingValue(doc.Content[0], "dependencies")
+		if deps == nil || deps.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, dep := range deps.Content {
+			name, version, repository := yamlMappingValue(dep, "name"), yamlMappingValue(dep, "version"),
+				yamlMappingValue(dep, "repository")
+			if name == nil || version == nil || repository == nil || !strings.HasPrefix(repository.Value, "file://") {
+				continue
+			}
+			for _, to := range packages {
+				if to.Name != name.Value || to == p {
+					continue
+				}
+				span, err := yamlNodeSpan(content, version, p.File+" dependency "+to.Name)
+				if err != nil {
+					return err
+				}
+				w.addRef(p.Name, to, p.File, content, span)
+			}
+		}
+	}
+	w.Packages = append(w.Packages, packages...)
+	return nil
+}
+
+// addRef records the requirement at span of the content of file, where from depends on the package to
+func (w *Workspace) addRef(from string, to *Package, file string, content []byte, span tomlSpan) {
+	w.refs = append(w.refs, reference{from: from, to: to, file: file, span: span, value: string(content[span.start:span.end])})
+}
@@ -0,0 +1,124 @@
+package bump
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// WriteFiles writes the content of every path in files as a single transaction: each file is first written to a
+// temporary file in its own directory, and only once all of them are written are they renamed over their paths. When a
+// rename fails, the files that were already replaced are restored (or removed when they did not exist), so that either
+// every file is updated or none is. A path that is a symlink is resolved first, so that its target is updated and the
+// link is kept.
+//
+// Example:
+// 		err := bump.WriteFiles(map[string][]byte{
+// 			"VERSION":      []byte("v1.3.0"),
+// 			"package.json": []byte(`{"version": "1.3.0"}`),
+// 		})
+func WriteFiles(files map[string][]byte) error {
+	resolved := make(map[string][]byte, len(files))
+	for path, content := range files {
+		target, err := filepath.EvalSymlinks(path)
+		if os.IsNotExist(err) {
+			target, err = path, nil
+		}
+		if err != nil {
+			return err
+		}
+		resolved[target] = content
+	}
+	files = resolved
+	paths := slices.Sorted(maps.Keys(files))
+	temps := make(map[string]string, len(paths))
+	originals := make(map[string]originalFile, len(paths)) // missing when the path did not exist
+	removeTemps := func() {
+		for _, temp := range temps {
+			_ = os.Remove(temp)
+		}
+	}
+	for _, path := range paths {
+		perm := fs.FileMode(0644)
+		if info, err := os.Stat(path); err == nil {
+			perm = info.Mode().Perm()
+			content, err := os.ReadFile(path)
+			if err != nil {
+				removeTemps()
+				return err
+			}
+			originals[path] = originalFile{content: content, perm: perm}
+		} else if !os.IsNotExist(err) {
+			removeTemps()
+			return err
+		}
+		temp, err := writeTemp(path, files[path], perm)
+		if err != nil {
+			removeTemps()
+			return fmt.Errorf("could not write %s: %w", path, err)
+		}
+		temps[path] = temp
+	}
+	for i, path := range paths {
+		if err := os.Rename(temps[path], path); err != nil {
+			removeTemps()
+			return errors.Join(fmt.Errorf("could not replace %s: %w", path, err), restoreFiles(paths[:i], originals))
+		}
+	}
+	return nil
+}
+
+// writeTemp writes content with perm to a new temporary file next to path and returns its name
+func writeTemp(path string, content []byte, perm fs.FileMode) (string, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".bump-*")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(content)
+	if err == nil {
+		err = f.Chmod(perm)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// originalFile is the content and permissions of a path before WriteFiles replaced it
+type originalFile struct {
+	content []byte
+	perm    fs.FileMode
+}
+
+// restoreFiles rolls back the paths replaced by WriteFiles to their originals with their original permissions, removing
+// those that did not exist
+func restoreFiles(paths []string, originals map[string]originalFile) error {
+	var errs []error
+	for _, path := range paths {
+		original, ok := originals[path]
+		if !ok {
+			if err := os.Remove(path); err != nil {
+				errs = append(errs, fmt.Errorf("could not roll back %s: %w", path, err))
+			}
+			continue
+		}
+		temp, err := writeTemp(path, original.content, original.perm)
+		if err == nil {
+			if err = os.Rename(temp, path); err != nil {
+				_ = os.Remove(temp)
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not roll back %s: %w", path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
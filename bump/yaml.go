@@ -0,0 +1,91 @@
+package bump
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlScalar locates the scalar value at the path of mapping keys inside a YAML document using the yaml.v3 node tree,
+// so the value can be replaced with replaceSpan while preserving comments, key order, quoting and formatting
+// byte-for-byte. The returned span excludes any surrounding quotes.
+func yamlScalar(content []byte, keys ...string) (tomlSpan, *yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return tomlSpan{}, nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return tomlSpan{}, nil, fmt.Errorf("%s key not found: empty document", strings.Join(keys, "."))
+	}
+	node := doc.Content[0]
+	for _, key := range keys {
+		next := yamlMappingValue(node, key)
+		if next == nil {
+			return tomlSpan{}, nil, fmt.Errorf("%s key not found", strings.Join(keys, "."))
+		}
+		node = next
+	}
+	span, err := yamlNodeSpan(content, node, strings.Join(keys, "."))
+	if err != nil {
+		return tomlSpan{}, nil, err
+	}
+	return span, node, nil
+}
+
+// yamlNodeSpan locates the single line scalar node inside content, where name describes the node in errors
+func yamlNodeSpan(content []byte, node *yaml.Node, name string) (tomlSpan, error) {
+	if node.Kind != yaml.ScalarNode {
+		return tomlSpan{}, fmt.Errorf("%s is not a scalar", name)
+	}
+	start, ok := yamlOffset(content, node.Line, node.Column)
+	if !ok {
+		return tomlSpan{}, fmt.Errorf("%s could not be located at line %d", name, node.Line)
+	}
+	switch node.Style {
+	case yaml.DoubleQuotedStyle, yaml.SingleQuotedStyle:
+		start++
+	case yaml.LiteralStyle, yaml.FoldedStyle:
+		return tomlSpan{}, fmt.Errorf("%s is a block scalar and cannot be replaced", name)
+	}
+	span := tomlSpan{start: start, end: start + len(node.Value)}
+	if span.end > len(content) || !bytes.Equal(content[span.start:span.end], []byte(node.Value)) {
+		return tomlSpan{}, fmt.Errorf("%s is not a single line scalar", name)
+	}
+	return span, nil
+}
+
+// yamlMappingValue returns the value node of key inside the mapping node, or nil
+func yamlMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// yamlOffset converts the 1-based line and rune column reported by yaml.v3 into a byte offset of content
+func yamlOffset(content []byte, line, column int) (int, bool) {
+	offset := 0
+	for l := 1; l < line; l++ {
+		i := bytes.IndexByte(content[offset:], '\n')
+		if i < 0 {
+			return 0, false
+		}
+		offset += i + 1
+	}
+	for c := 1; c < column; c++ {
+		if offset >= len(content) || content[offset] == '\n' {
+			return 0, false
+		}
+		_, size := utf8.DecodeRune(content[offset:])
+		offset += size
+	}
+	return offset, true
+}
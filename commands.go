@@ -0,0 +1,524 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/andreimerlescu/bump/bump"
+)
+
+// commands maps the name of a bump sub-command to the func that runs it with the remaining positional arguments and
+// returns the exit code of the process
+var commands = map[string]func(args []string) int{
+	"compare":   compareCommand,
+	"sort":      sortCommand,
+	"max":       maxCommand,
+	"min":       minCommand,
+	"satisfies": satisfiesCommand,
+	"verify":    verifyCommand,
+	"sync":      syncCommand,
+	"workspace": workspaceCommand,
+}
+
+// comparison stores the compare output of two versions
+type comparison struct {
+	A      string `json:"a"`
+	B      string `json:"b"`
+	Result int    `json:"result"`
+}
+
+// satisfaction stores the satisfies output of a version against a range
+type satisfaction struct {
+	Version   string `json:"version"`
+	Range     string `json:"range"`
+	Satisfies bool   `json:"satisfies"`
+}
+
+// sourceResult stores the version of one of the files found by verify and sync
+type sourceResult struct {
+	File    string `json:"file"`
+	Version string `json:"version,omitempty"`
+	Match   bool   `json:"match"`
+	Synced  string `json:"synced,omitempty"` // version written by sync
+	Error   string `json:"error,omitempty"`  // why the file was skipped
+}
+
+// consistency stores the verify and sync output of the version files of a repository against the source file
+type consistency struct {
+	Source  string         `json:"source"`
+	Version string         `json:"version"`
+	Drift   bool           `json:"drift"`
+	Files   []sourceResult `json:"files"`
+}
+
+// parsed pairs the original input of a version with its parsed Version
+type parsed struct {
+	input   string
+	version *bump.Version
+}
+
+// runCommand parses the flags that follow the sub-command name in args and exits with the code of the sub-command
+func runCommand(args []string) {
+	cmd, ok := commands[args[0]]
+	if !ok {
+		_, _ = fmt.Fprintf(os.Stderr, "unknown command %q\n", args[0])
+		os.Exit(1)
+	}
+	check(flag.CommandLine.Parse(args[1:]))
+	applyFlags()
+	resolveInputFile()
+	os.Exit(cmd(flag.Args()))
+}
+
+// compareCommand compares two versions and exits 0 when they are equal, 1 when the first is greater and 2 when the
+// first is lower; invalid input exits 3
+func compareCommand(args []string) int {
+	if len(args) != 2 {
+		_, _ = fmt.Fprintln(os.Stderr, "Usage: bump compare [-json] VERSION_A VERSION_B")
+		return 3
+	}
+	versions, err := parseAll(args)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "Error parsing version:", err)
+		return 3
+	}
+	c := versions[0].version.Compare(versions[1].version)
+	if useJson {
+		printJson(&comparison{A: args[0], B: args[1], Result: c})
+	} else {
+		fmt.Printf("%s %s %s\n", args[0], map[int]string{-1: "<", 0: "==", 1: ">"}[c], args[1])
+	}
+	if c < 0 {
+		return 2
+	}
+	return c
+}
+
+// satisfiesCommand checks the versions in args, or the -in file when no args are provided, against -range and exits 0
+// when all of them satisfy it, 1 when any does not and 2 on invalid input
+func satisfiesCommand(args []string) int {
+	if len(versionRange) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "Usage: bump satisfies -range=RANGE [-in=FILE] [-json] [VERSION...]")
+		return 2
+	}
+	c, err := bump.ParseConstraint(versionRange)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "Error parsing range:", err)
+		return 2
+	}
+	var versions []parsed
+	if len(args) > 0 {
+		if versions, err = parseAll(args); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, "Error parsing version:", err)
+			return 2
+		}
+	} else {
+		v := NewVersion()
+		versions = append(versions, parsed{input: v.Format(!v.NoPrefix()), version: v})
+	}
+	code := 0
+	results := make([]satisfaction, 0, len(versions))
+	for _, p := range versions {
+		ok := c.Satisfies(p.version)
+		if !ok {
+			code = 1
+		}
+		results = append(results, satisfaction{Version: p.input, Range: c.String(), Satisfies: ok})
+	}
+	if useJson {
+		if len(results) == 1 {
+			printJson(results[0])
+		} else {
+			printJson(results)
+		}
+		return code
+	}
+	for _, r := range results {
+		if r.Satisfies {
+			fmt.Printf("%s satisfies %s\n", r.Version, r.Range)
+		} else {
+			fmt.Printf("%s does not satisfy %s\n", r.Version, r.Range)
+		}
+	}
+	return code
+}
+
+// verifyCommand compares the version of every file of the repository at DIR (default .) that bump.FindVersionFiles finds
+// with the version of the -in file, or of the first file found when -in does not exist, and exits 0 when all of them
+// match, 1 when any drifts and 2 on invalid input
+func verifyCommand(args []string) int {
+	c, _, _, err := scanSources(args, false)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "Usage: bump verify [-in=FILE] [-json] [DIR]:", err)
+		return 2
+	}
+	code := 0
+	if c.Drift {
+		code = 1
+	}
+	if useJson {
+		printJson(c)
+		return code
+	}
+	drifted, parsedFiles := 0, 0
+	for _, f := range c.Files {
+		if len(f.Error) == 0 {
+			parsedFiles++
+		}
+		switch {
+		case len(f.Error) > 0:
+			fmt.Printf("%s: skipped (%s)\n", f.File, f.Error)
+		case f.File == c.Source:
+			fmt.Printf("%s: %s (source)\n", f.File, f.Version)
+		case f.Match:
+			fmt.Printf("%s: %s\n", f.File, f.Version)
+		default:
+			fmt.Printf("%s: %s (drift)\n", f.File, f.Version)
+			drifted++
+		}
+	}
+	if c.Drift {
+		fmt.Printf("%d of %d files drift from %s of %s\n", drifted, parsedFiles, c.Version, c.Source)
+	} else {
+		fmt.Printf("All files match %s of %s\n", c.Version, c.Source)
+	}
+	return code
+}
+
+// syncCommand assigns the version of the -in file to every file of the repository at DIR (default .) that drifts from
+// it, saving all of them in a single transaction with -write, and exits 0 on success and 2 on invalid input
+func syncCommand(args []string) int {
+	c, source, versions, err := scanSources(args, true)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "Usage: bump sync [-in=FILE] [-write] [-json] [DIR]:", err)
+		return 2
+	}
+	var changed []*bump.Version
+	for i, v := range versions {
+		if v == nil || c.Files[i].Match {
+			continue
+		}
+		v.Assign(source)
+		c.Files[i].Synced = v.Format(!v.NoPrefix())
+		changed = append(changed, v)
+	}
+	if writeInput && len(changed) > 0 {
+		if err := bump.SaveAll(changed...); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, "Error saving:", err)
+			return 2
+		}
+	}
+	if useJson {
+		printJson(c)
+		return 0
+	}
+	switch {
+	case len(changed) == 0:
+		fmt.Printf("All files match %s of %s\n", c.Version, c.Source)
+	case writeInput:
+		fmt.Printf("Synced %d files to %s of %s\n", len(changed), c.Version, c.Source)
+	default:
+		fmt.Printf("Sync %d files to %s of %s (use -write to save)\n", len(changed), c.Version, c.Source)
+	}
+	for _, f := range c.Files {
+		if len(f.Synced) > 0 {
+			fmt.Printf("  %s: %s → %s\n", f.File, f.Version, f.Synced)
+		}
+	}
+	return 0
+}
+
+// workspaceCommand lists the packages of the workspace at DIR (default .) found by bump.DiscoverWorkspace, or with a bump
+// flag plans the bump of the -package and -changed packages along with the requirements of their dependents, saving
+// them with -write, and exits 0 on success and 2 on invalid input
+func workspaceCommand(args []string) int {
+	usage := "Usage: bump workspace [-package=NAME|PATH] [-changed] [-major|-minor|-patch|...] [-write] [-json] [DIR]:"
+	if len(args) > 1 {
+		_, _ = fmt.Fprintln(os.Stderr, usage, "expected a single DIR")
+		return 2
+	}
+	root := "."
+	if len(args) == 1 {
+		root = args[0]
+	}
+	w, err := bump.DiscoverWorkspace(root)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, usage, err)
+		return 2
+	}
+	packages := w.Packages
+	if len(packageNames) > 0 {
+		packages = nil
+		for _, name := range strings.Split(packageNames, ",") {
+			p, err := w.Find(strings.TrimSpace(name))
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, usage, err)
+				return 2
+			}
+			packages = append(packages, p)
+		}
+	}
+	if changedOnly {
+		var changed []*bump.Package
+		for _, p := range packages {
+			if len(p.Version) == 0 {
+				continue
+			}
+			ok, err := w.Changed(p)
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, usage, err)
+				return 2
+			}
+			if ok {
+				changed = append(changed, p)
+			}
+		}
+		packages = changed
+	}
+
+	bumpFlags, err := validate()
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, usage, err)
+		return 2
+	}
+	if bumpFlags == 0 && len(preRelease) == 0 {
+		if useJson {
+			printJson(packages)
+			return 0
+		}
+		for _, p := range packages {
+			fmt.Printf("%s %s (%s, %s)", p.Name, p.Version, p.Kind, p.File)
+			if len(p.Dependencies) > 0 {
+				fmt.Printf(" depends on %s", strings.Join(p.Dependencies, ", "))
+			}
+			fmt.Println()
+		}
+		return 0
+	}
+	if len(packageNames) == 0 && !changedOnly {
+		_, _ = fmt.Fprintln(os.Stderr, usage, "use -package or -changed to select the packages to bump")
+		return 2
+	}
+	plan, err := w.Plan(packages, func(v *bump.Version) {
+		before := snapshot(v)
+		run(v)
+		if len(preRelease) > 0 {
+			check(v.SetPreRelease(preRelease))
+		}
+		check(refuseDowngrade(before, v))
+	})
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, usage, err)
+		return 2
+	}
+	if writeInput {
+		if err := plan.Write(); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, "Error saving:", err)
+			return 2
+		}
+	}
+	if useJson {
+		printJson(plan)
+		return 0
+	}
+	switch {
+	case len(plan.Packages) == 0:
+		fmt.Println("No packages to bump")
+	case writeInput:
+		fmt.Printf("Bumped %d packages (saved %d files)\n", len(plan.Packages), len(plan.Files))
+	default:
+		fmt.Printf("Bump %d packages (%d files, use -write to save)\n", len(plan.Packages), len(plan.Files))
+	}
+	for _, p := range plan.Packages {
+		fmt.Printf("  %s: %s → %s (%s, tag %s)\n", p.Name, p.Old, p.New, p.File, p.Tag)
+	}
+	for _, r := range plan.References {
+		fmt.Printf("  %s: %s %s → %s\n", r.File, r.Dependency, r.Old, r.New)
+	}
+	return 0
+}
+
+// scanSources parses every file that bump.FindVersionFiles finds in the DIR of args and compares it with the source,
+// which is the -in file (resolved against DIR unless -in is set) or, unless requireSource, the first file found when it
+// does not exist; the returned versions line up with the Files of the consistency and are nil for skipped files
+func scanSources(args []string, requireSource bool) (*consistency, *bump.Version, []*bump.Version, error) {
+	if len(args) > 1 {
+		return nil, nil, nil, errors.New("expected a single DIR")
+	}
+	root := "."
+	if len(args) == 1 {
+		root = args[0]
+	}
+	paths, err := bump.FindVersionFiles(root)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sourcePath := inputFile
+	if lookupSetting("input").Source == sourceDefault {
+		sourcePath = filepath.Join(root, inputFile)
+	}
+	if _, err := os.Stat(sourcePath); err != nil {
+		if requireSource || len(paths) == 0 {
+			return nil, nil, nil, err
+		}
+		sourcePath = ""
+	}
+	if len(sourcePath) > 0 && !slices.ContainsFunc(paths, func(p string) bool { return samePath(p, sourcePath) }) {
+		paths = append([]string{sourcePath}, paths...)
+	}
+
+	c := &consistency{Files: make([]sourceResult, len(paths))}
+	versions := make([]*bump.Version, len(paths))
+	var source *bump.Version
+	for i, path := range paths {
+		c.Files[i].File = path
+		v, err := loadVersion(path)
+		if err != nil {
+			c.Files[i].Error = err.Error()
+			continue
+		}
+		versions[i] = v
+		c.Files[i].Version = v.Format(!v.NoPrefix())
+		if source == nil && (len(sourcePath) == 0 || samePath(path, sourcePath)) {
+			source, c.Source, c.Version = v, path, c.Files[i].Version
+		}
+	}
+	if source == nil {
+		if len(sourcePath) > 0 {
+			return nil, nil, nil, fmt.Errorf("could not parse source %s", sourcePath)
+		}
+		return nil, nil, nil, fmt.Errorf("could not find a version file in %s", root)
+	}
+	for i, v := range versions {
+		if v == nil {
+			continue
+		}
+		c.Files[i].Match = v.Compare(source) == 0 && slices.Equal(v.Build, source.Build)
+		c.Drift = c.Drift || !c.Files[i].Match
+	}
+	return c, source, versions, nil
+}
+
+// samePath reports whether the paths a and b refer to the same file
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// sortCommand prints the versions from args (or STDIN) in ascending order, or descending order with -desc
+func sortCommand(args []string) int {
+	versions, ok := readVersions(args)
+	if !ok {
+		return 1
+	}
+	sortVersions(versions)
+	out := make([]string, 0, len(versions))
+	for _, p := range versions {
+		out = append(out, p.input)
+	}
+	if useJson {
+		printJson(out)
+	} else {
+		for _, o := range out {
+			fmt.Println(o)
+		}
+	}
+	return 0
+}
+
+// maxCommand prints the highest version from args (or STDIN)
+func maxCommand(args []string) int {
+	return extremeCommand(args, true)
+}
+
+// minCommand prints the lowest version from args (or STDIN)
+func minCommand(args []string) int {
+	return extremeCommand(args, false)
+}
+
+// extremeCommand prints the highest (or lowest) version from args (or STDIN)
+func extremeCommand(args []string, highest bool) int {
+	versions, ok := readVersions(args)
+	if !ok {
+		return 1
+	}
+	if len(versions) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "No versions provided")
+		return 1
+	}
+	pick := versions[0]
+	for _, p := range versions[1:] {
+		c := p.version.Compare(pick.version)
+		if (highest && c > 0) || (!highest && c < 0) {
+			pick = p
+		}
+	}
+	if useJson {
+		printJson(&result{Version: pick.input})
+	} else {
+		fmt.Println(pick.input)
+	}
+	return 0
+}
+
+// readVersions parses args, or every whitespace separated value from STDIN when no args are provided, and reports
+// parse errors to STDERR
+func readVersions(args []string) ([]parsed, bool) {
+	if len(args) == 0 {
+		var err error
+		args, err = readFields(os.Stdin)
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, "Error reading STDIN:", err)
+			return nil, false
+		}
+	}
+	versions, err := parseAll(args)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "Error parsing version:", err)
+		return nil, false
+	}
+	return versions, true
+}
+
+// readFields returns every whitespace separated value of r
+func readFields(r io.Reader) ([]string, error) {
+	var fields []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields = append(fields, strings.Fields(scanner.Text())...)
+	}
+	return fields, scanner.Err()
+}
+
+// parseAll uses bump.Parse on every input
+func parseAll(inputs []string) ([]parsed, error) {
+	versions := make([]parsed, 0, len(inputs))
+	for _, in := range inputs {
+		v, err := bump.Parse(strings.TrimSpace(in))
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, parsed{input: strings.TrimSpace(in), version: v})
+	}
+	return versions, nil
+}
+
+// sortVersions stable sorts versions ascending using bump.Version.Compare, or descending when -desc is used
+func sortVersions(versions []parsed) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		c := versions[i].version.Compare(versions[j].version)
+		if descending {
+			return c > 0
+		}
+		return c < 0
+	})
+}
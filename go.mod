@@ -2,11 +2,13 @@ module github.com/andreimerlescu/bump
 
 go 1.24.5
 
-require github.com/stretchr/testify v1.10.0
+require (
+	github.com/andreimerlescu/checkfs v1.0.4
+	github.com/stretchr/testify v1.10.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
-	github.com/andreimerlescu/checkfs v1.0.4 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -3,14 +3,19 @@ package main
 import (
 	"embed"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"maps"
 	"os"
+	"os/exec"
 	"path/filepath"
-	"strconv"
+	"slices"
 	"strings"
 	"sync/atomic"
+	"text/template"
+	"time"
 
 	"github.com/andreimerlescu/bump/bump"
 )
@@ -26,6 +31,31 @@ const (
 	envNoAlpha        = "BUMP_NO_ALPHA"          // ENV prevents -alpha usage
 	envNoBeta         = "BUMP_NO_BETA"           // ENV prevents -beta usage
 	envNoRC           = "BUMP_NO_RC"             // ENV prevents -rc usage
+	envAppVersion     = "BUMP_APP_VERSION"       // ENV defines default -app-version
+	envChannels       = "BUMP_CHANNELS"          // ENV limits the pre-release channels that can be bumped
+	envPrefix         = "BUMP_PREFIX"            // ENV keeps, always adds or never adds the "v" prefix
+	envJson           = "BUMP_JSON"              // ENV always sets -json
+	envPomTarget      = "BUMP_POM_TARGET"        // ENV defines default -pom-target
+	envDockerKey      = "BUMP_DOCKER_KEY"        // ENV defines default -docker-key
+	envGoSource       = "BUMP_GO"                // ENV defines default -go
+	envPreHook        = "BUMP_PRE_HOOK"          // ENV command run before the -in file is written
+	envPostHook       = "BUMP_POST_HOOK"         // ENV command run after the -in file is written
+	envFrom           = "BUMP_FROM"              // ENV defines default -from
+	envTagPrefix      = "BUMP_TAG_PREFIX"        // ENV defines default -tag-prefix
+	envCommitTypes    = "BUMP_COMMIT_TYPES"      // ENV maps the Conventional Commits types to the bump level of -auto
+	envChangelog      = "BUMP_CHANGELOG"         // ENV defines default -changelog
+	envCommitMessage  = "BUMP_COMMIT_MESSAGE"    // ENV defines default -commit-message
+	envTagMessage     = "BUMP_TAG_MESSAGE"       // ENV defines default -tag-message
+	envSign           = "BUMP_SIGN"              // ENV always sets -sign
+	envLadder         = "BUMP_LADDER"            // ENV orders the pre-release channels of -promote and -channel
+	envPre            = "BUMP_PRE"               // ENV defines default -pre
+
+	prefixKeep   = "keep"   // prefix setting that keeps the "v" prefix of the -in file as it is
+	prefixAlways = "always" // prefix setting that adds a "v" prefix
+	prefixNever  = "never"  // prefix setting that removes the "v" prefix
+
+	fromFile = "file" // from setting that reads the version from the -in file
+	fromGit  = "git"  // from setting that reads the version from the highest git tag reachable from HEAD
 
 	VFN = "VERSION"
 )
@@ -35,6 +65,58 @@ type result struct {
 	Version string `json:"version"`
 }
 
+// fileResult stores the version of one of the -in files before and after the bump
+type fileResult struct {
+	File string `json:"file"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+// bumpResult stores the -json output of a bump, which is the Version of the first -in file and every -in file it touched
+type bumpResult struct {
+	*bump.Version
+	Files []fileResult `json:"files"`
+	Tag   string       `json:"tag,omitempty"`
+
+	Level   string        `json:"level,omitempty"`   // bump level picked by -auto
+	Commits []bump.Commit `json:"commits,omitempty"` // commits that imply the level picked by -auto
+
+	Changelog string `json:"changelog,omitempty"` // new section of the -changelog file
+	Commit    string `json:"commit,omitempty"`    // abbreviated hash of the commit created by -commit
+}
+
+// releaseData is the data of the -commit-message and -tag-message templates
+type releaseData struct {
+	Old   string   // version before the bump
+	New   string   // version after the bump
+	Tag   string   // git tag of the new version
+	Files []string // files written by the bump
+}
+
+// fileList is the flag.Value of -in, where repeating -in (or separating files with commas) adds another file
+type fileList struct {
+	files []string
+	set   bool
+}
+
+// String returns the comma separated files of the fileList
+func (l *fileList) String() string {
+	return strings.Join(l.files, ",")
+}
+
+// Set adds the comma separated files of value to the fileList, replacing the default files on its first call
+func (l *fileList) Set(value string) error {
+	if !l.set {
+		l.files, l.set = nil, true
+	}
+	for _, f := range strings.Split(value, ",") {
+		if f = strings.TrimSpace(f); len(f) > 0 {
+			l.files = append(l.files, f)
+		}
+	}
+	return nil
+}
+
 // binaryVersionBytes contains the embedded VERSION file's contents
 //
 //go:embed VERSION
@@ -60,8 +142,23 @@ func BinaryVersion() string {
 var (
 	initialInputFile = filepath.Join(".", VFN)
 
-	shouldParse string // flag.StringVar -parse
-	inputFile   string // flag.StringVar -in
+	shouldParse  string // flag.StringVar -parse
+	inputFile    string // first of the -in files, the source of truth of the version
+	preRelease   string // flag.StringVar -prerelease
+	buildMeta    string // flag.StringVar -build
+	versionRange string // flag.StringVar -range
+	appVersion   string // flag.StringVar -app-version
+	pomTarget    string // flag.StringVar -pom-target
+	dockerKey    string // flag.StringVar -docker-key
+	goSource     string // flag.StringVar -go
+	packageNames string // flag.StringVar -package
+	versionFrom  string // flag.StringVar -from
+	tagPrefix    string // flag.StringVar -tag-prefix
+	changelog    string // flag.StringVar -changelog
+	commitMsg    string // flag.StringVar -commit-message
+	tagMsg       string // flag.StringVar -tag-message
+	channel      string // flag.StringVar -channel
+	preLevel     string // flag.StringVar -pre
 
 	showVersion bool // flag.BoolVar -v
 	shouldInit  bool // flag.BoolVar -init
@@ -75,50 +172,49 @@ var (
 	major       bool // flag.BoolVar -major
 	minor       bool // flag.BoolVar -minor
 	patch       bool // flag.BoolVar -patch
+	revision    bool // flag.BoolVar -revision
+	release     bool // flag.BoolVar -release
+	goModule    bool // flag.BoolVar -go-module
+	forceMajor  bool // flag.BoolVar -force
 	alpha       bool // flag.BoolVar -alpha
 	beta        bool // flag.BoolVar -beta
 	rc          bool // flag.BoolVar -rc
+	buildGit    bool // flag.BoolVar -build-git
+	buildDate   bool // flag.BoolVar -build-date
+	descending  bool // flag.BoolVar -desc
+	changedOnly bool // flag.BoolVar -changed
+	writeTag    bool // flag.BoolVar -tag and -write-tag
+	autoBump    bool // flag.BoolVar -auto
+	commitBump  bool // flag.BoolVar -commit
+	signRelease bool // flag.BoolVar -sign
+	promote     bool // flag.BoolVar -promote
+	downgrade   bool // flag.BoolVar -allow-downgrade
+
+	inputFiles fileList // flag.Var -in
+
+	linked  []*bump.Version // versions of the -in files after the first, kept in lockstep with the first
+	touched []fileResult    // every -in file and its version before and after the bump
+	newTag  string          // git tag of the new version for -tag
+	tagged  string          // git tag created by -tag, set once bump.GitTag succeeds
+	saved   []string        // files written by save
+	commit  string          // abbreviated hash of the commit created by -commit
+	level   string          // bump level picked by -auto
+	drivers []bump.Commit   // commits that imply the bump level picked by -auto
+
+	changelogContent []byte // new content of the -changelog file, saved along with the -in files
+	changelogSection string // new section of the -changelog file
+	changelogSaved   bool   // whether save wrote the -changelog file
 )
 
-// appEnv renders a KEY=VAL\nKEY=VAL\n string of bump ENV variable customization options
+// appEnv renders a KEY=VAL # SOURCE\nKEY=VAL # SOURCE\n string of the effective bump settings and where they came from
 func appEnv(indent string) string {
 	var out strings.Builder
-	defaultInput := envVal(envDefaultInput, initialInputFile)
-	for e, v := range map[string]string{
-		envAlwaysWrite:    strconv.FormatBool(envIs(envAlwaysWrite)),
-		envDefaultInput:   envVal(envDefaultInput, defaultInput),
-		envNeverFix:       strconv.FormatBool(envIs(envNeverFix)),
-		envNoAlpha:        strconv.FormatBool(envIs(envNoAlpha)),
-		envNoBeta:         strconv.FormatBool(envIs(envNoBeta)),
-		envNoAlphaBeta:    strconv.FormatBool(envIs(envNoAlphaBeta)),
-		envNoRC:           strconv.FormatBool(envIs(envNoRC)),
-		envNoPreview:      strconv.FormatBool(envIs(envNoPreview)),
-		envInitOnNotFound: strconv.FormatBool(envIs(envInitOnNotFound)),
-		envAlwaysFix:      strconv.FormatBool(envIs(envAlwaysFix)),
-	} {
-		out.WriteString(fmt.Sprintf("%s%s=%s\n", indent, e, v))
+	for _, s := range settings {
+		out.WriteString(fmt.Sprintf("%s%s=%s # %s\n", indent, s.Env, s.Value, s.Source))
 	}
 	return out.String()
 }
 
-// envIs combines os.LookupEnv to strconv.ParseBool
-func envIs(name string) bool {
-	v, ok := os.LookupEnv(name)
-	if !ok {
-		return false
-	}
-	vb, err := strconv.ParseBool(v)
-	return err == nil && vb
-}
-
-// envVal combines os.LookupEnv to fallback
-func envVal(name, fallback string) string {
-	if v, ok := os.LookupEnv(name); ok {
-		return v
-	}
-	return fallback
-}
-
 // about prints a helpful bump usage description to STDOUT
 func about() {
 	var out strings.Builder
@@ -126,13 +222,34 @@ func about() {
 	out.WriteString("Usage:\n")
 	out.WriteString("  bump -check [-in=FILE]\n")
 	out.WriteString("  bump -fix [-write] [-in=FILE]\n")
+	out.WriteString("  bump -[major|minor|patch|...] [-write] -in=FILE -in=FILE... [-json]\n")
 	out.WriteString("  bump -[major|minor|patch|alpha|beta|rc|preview] [-write] [-in=FILE] [-json]\n")
+	out.WriteString("  bump -revision [-write] [-in=FILE] [-json]\n")
+	out.WriteString("  bump -auto [-from=git] [-tag-prefix=PREFIX] [-write] [-json]\n")
+	out.WriteString("  bump -[major|minor|patch|auto|...] -changelog=CHANGELOG.md [-write] [-json]\n")
+	out.WriteString("  bump -from=git [-tag-prefix=PREFIX] -[major|minor|patch|...] [-write] [-tag] [-json]\n")
+	out.WriteString("  bump -[major|minor|patch|auto|...] -commit [-commit-message=TEMPLATE] [-tag] [-tag-message=TEMPLATE] [-sign]\n")
+	out.WriteString("  bump -release [-write] [-in=FILE] [-json]\n")
+	out.WriteString("  bump -promote [-write] [-in=FILE] [-json]\n")
+	out.WriteString("  bump [-major|-minor|-patch] -channel=[alpha|beta|rc|...] [-write] [-in=FILE] [-json]\n")
+	out.WriteString("  bump -go-module -major [-force] [-tag-prefix=PREFIX] [-write] [-in=go.mod] [-json]\n")
+	out.WriteString("  bump -go=[auto|local|toolchain|igo|VERSION] -fix [-write] [-in=go.mod]\n")
+	out.WriteString("  bump -app-version=[ignore|sync|derive|only] [-check|-major|...] [-write] [-in=Chart.yaml]\n")
+	out.WriteString("  bump [-major|-minor|-patch] -prerelease=IDENTIFIERS [-write] [-in=FILE] [-json]\n")
+	out.WriteString("  bump [-build=META] [-build-date] [-build-git] [-write] [-in=FILE] [-json]\n")
+	out.WriteString("  bump compare [-json] VERSION_A VERSION_B\n")
+	out.WriteString("  bump sort [-desc] [-json] [VERSION...]\n")
+	out.WriteString("  bump [max|min] [-json] [VERSION...]\n")
+	out.WriteString("  bump satisfies -range=RANGE [-in=FILE] [-json] [VERSION...]\n")
+	out.WriteString("  bump verify [-in=FILE] [-json] [DIR]\n")
+	out.WriteString("  bump sync [-in=FILE] [-write] [-json] [DIR]\n")
+	out.WriteString("  bump workspace [-package=NAME|PATH] [-changed] [-major|-minor|-patch|...] [-write] [-json] [DIR]\n")
 	out.WriteString("Supported File Types:\n")
 	for _, t := range bump.SupportedFiles {
 		out.WriteString(fmt.Sprintf("  %s\n", t))
 	}
 	out.WriteString("Defaults: \n")
-	out.WriteString(fmt.Sprintf("  -in=%s [default: %s]\n", inputFile, envVal(envDefaultInput, initialInputFile)))
+	out.WriteString(fmt.Sprintf("  -in=%s [default: %s]\n", inputFiles.String(), settingVal("input")))
 	out.WriteString("Environment Variables:\n")
 	out.WriteString(appEnv("  "))
 	fmt.Print(out.String())
@@ -144,10 +261,14 @@ func NewVersion() *bump.Version {
 	if versionCalls.Load() > 3 {
 		return nil
 	}
+	if versionFrom == fromGit {
+		return gitVersion()
+	}
 	v := bump.New()
+	configure(v)
 	err := v.LoadFile(inputFile)
 	if err != nil {
-		if strings.HasSuffix(inputFile, VFN) && os.IsNotExist(err) && (envIs(envInitOnNotFound) || shouldInit) {
+		if strings.HasSuffix(inputFile, VFN) && os.IsNotExist(err) && (settingIs("init_on_not_found") || shouldInit) {
 			var err2 error
 			if len(shouldParse) > 0 {
 				err2 = os.WriteFile(inputFile, []byte(shouldParse), 0644)
@@ -189,15 +310,152 @@ func NewVersion() *bump.Version {
 		}
 		os.Exit(1)
 	}
-	if envIs(envAlwaysFix) && envIs(envNeverFix) {
+	applyPrefix(v)
+	if settingIs("always_fix") && settingIs("never_fix") {
 		_, _ = fmt.Fprintf(os.Stderr, "env %s and %s cannot be used together", envAlwaysFix, envNeverFix)
 		os.Exit(1)
 	}
 	return v
 }
 
+// gitVersion returns the version of the highest -tag-prefix tag reachable from HEAD for -from=git, starting from 0.0.0
+// with -init when there is none; the version is assigned to the -in file when it exists so that -write keeps the file in
+// step with the tags
+func gitVersion() *bump.Version {
+	_, tagged, err := bump.GitLatestTag(filepath.Dir(inputFile), tagPrefix)
+	check(err)
+	if tagged == nil {
+		if !shouldInit {
+			check(fmt.Errorf("no %s* tag is reachable from HEAD, use -init to start from 0.0.0", tagPrefix))
+		}
+		initial := "0.0.0"
+		if strings.HasSuffix(tagPrefix, "v") {
+			initial = "v0.0.0"
+		}
+		tagged, err = bump.Parse(initial)
+		check(err)
+	}
+	if _, err := os.Stat(inputFile); err == nil {
+		v, err := loadVersion(inputFile)
+		check(err)
+		v.Assign(tagged)
+		return v
+	}
+	if writeInput {
+		check(fmt.Errorf("-from=git only writes to an existing -in file, %s does not exist", inputFile))
+	}
+	applyPrefix(tagged)
+	return tagged
+}
+
+// tagName returns the git tag of version, which is -tag-prefix followed by the version without its "v" prefix
+func tagName(version *bump.Version) string {
+	return tagPrefix + strings.TrimPrefix(version.Format(false), "v")
+}
+
+// resolveInputFile sets inputFile to the first of the -in files once the flags are parsed
+func resolveInputFile() {
+	inputFile = initialInputFile
+	if len(inputFiles.files) > 0 {
+		inputFile = inputFiles.files[0]
+	}
+}
+
+// configure applies the file specific flags to v before it is parsed
+func configure(v *bump.Version) {
+	check(v.SetAppVersionPolicy(appVersion))
+	check(v.SetPomTarget(pomTarget))
+	check(v.SetDockerKey(dockerKey))
+	check(v.SetGoSource(goSource))
+}
+
+// applyPrefix applies the prefix setting to the parsed v
+func applyPrefix(v *bump.Version) {
+	switch settingVal("prefix") {
+	case prefixAlways:
+		v.SetNoPrefix(false)
+	case prefixNever:
+		v.SetNoPrefix(true)
+	}
+}
+
+// linkedVersions loads and parses every -in file after the first, which receive the version of the first once it is
+// bumped so that all of them are saved in lockstep
+func linkedVersions() []*bump.Version {
+	if len(inputFiles.files) < 2 {
+		return nil
+	}
+	var versions []*bump.Version
+	for _, path := range inputFiles.files {
+		if filepath.Base(path) == bump.FileGoMod {
+			check(fmt.Errorf("%s holds the Go version and cannot be used with other -in files", path))
+		}
+	}
+	for _, path := range inputFiles.files[1:] {
+		v, err := loadVersion(path)
+		check(err)
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+// loadVersion loads, fixes (with -fix) and parses the file at path using the file specific flags and the prefix setting
+func loadVersion(path string) (*bump.Version, error) {
+	v := bump.New()
+	configure(v)
+	if err := v.LoadFile(path); err != nil {
+		return nil, err
+	}
+	if shouldFix {
+		if err := v.Fix(); err != nil {
+			return nil, fmt.Errorf("error fixing %s: %w", path, err)
+		}
+	}
+	if err := v.Parse(); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	applyPrefix(v)
+	return v, nil
+}
+
+// lockstep assigns the bumped version to every linked version and returns the touched -in files
+func lockstep(version *bump.Version, originalVersion, newVersion string) []fileResult {
+	results := []fileResult{{File: inputFile, Old: originalVersion, New: newVersion}}
+	for _, v := range linked {
+		old := v.Format(!v.NoPrefix())
+		v.Assign(version)
+		results = append(results, fileResult{File: v.Path(), Old: old, New: v.Format(!v.NoPrefix())})
+	}
+	return results
+}
+
+// savedTo describes where save writes the version, which is the -in file or the number of -in files
+func savedTo() string {
+	if len(touched) > 1 {
+		return fmt.Sprintf("%d files", len(touched))
+	}
+	return inputFile
+}
+
+// printTouched prints every touched -in file with its version before and after the bump when there is more than one
+func printTouched() {
+	if len(touched) < 2 {
+		return
+	}
+	for _, f := range touched {
+		fmt.Printf("  %s: %s → %s\n", f.File, f.Old, f.New)
+	}
+}
+
 func main() {
 	config()
+	if flag.NArg() > 0 {
+		runCommand(flag.Args())
+	}
+	if goModule {
+		migrateGoModule()
+		return
+	}
 	versionCalls.Store(0)
 	version := NewVersion()
 	if version == nil {
@@ -218,25 +476,88 @@ func main() {
 
 	bumpFlags, err := validate()
 	check(err)
+	linked = linkedVersions()
+	before := snapshot(version)
 
-	if bumpFlags > 0 {
+	if autoBump {
+		autoLevel(version)
+	}
+
+	if bumpFlags > 0 || len(preRelease) > 0 {
 		run(version)
 	}
 
+	if len(preRelease) > 0 {
+		check(version.SetPreRelease(preRelease))
+		bumpFlags++
+	}
+
+	if build := buildMetadata(); len(build) > 0 {
+		check(version.SetBuild(build))
+		bumpFlags++
+	}
+
 	newVersionStr := version.Format(version.NoPrefix() == false)
 	version.Version = newVersionStr // For JSON output
+	check(refuseDowngrade(before, version))
 	noChange := !strings.EqualFold(originalVersionStr, newVersionStr)
 	wasParsed := !strings.EqualFold(newVersionStr, shouldParse)
 	wasBumped := noChange || wasParsed || shouldInit
+	if autoBump && level == bump.LevelNone && !noChange {
+		wasBumped = false // no commit implies a bump
+	}
+	touched = lockstep(version, originalVersionStr, newVersionStr)
+	if len(changelog) > 0 && wasBumped && !strings.EqualFold(originalVersionStr, newVersionStr) {
+		promoteChangelog(version)
+	}
+
+	if writeTag || commitBump {
+		newTag = tagName(version)
+	}
+	if writeTag {
+		exists, err := bump.GitTagExists(filepath.Dir(inputFile), newTag)
+		check(err)
+		if exists {
+			check(fmt.Errorf("git tag %s already exists", newTag))
+		}
+	}
+	if commitBump {
+		checkStaged(version)
+	}
 
 	finish(version, wasBumped, bumpFlags, originalVersionStr, newVersionStr)
+
+	if autoBump && !useJson {
+		for _, c := range drivers {
+			fmt.Printf("  %s %s (%s)\n", c.Hash[:min(7, len(c.Hash))], c.Subject, c.Level)
+		}
+	}
+	if len(changelogSection) > 0 && !useJson {
+		if changelogSaved {
+			fmt.Printf("Updated %s\n", changelog)
+		} else {
+			fmt.Printf("Changelog %s (use -write to save):\n", changelog)
+			for _, line := range strings.Split(strings.TrimRight(changelogSection, "\n"), "\n") {
+				fmt.Println(strings.TrimRight("  "+line, " "))
+			}
+		}
+	}
+	if len(commit) > 0 && !useJson {
+		fmt.Printf("Committed %s %s\n", commit, firstLine(releaseMessage(commitMsg, originalVersionStr, newVersionStr)))
+	}
+	createTag(originalVersionStr, newVersionStr)
+	if len(tagged) > 0 && !useJson {
+		fmt.Printf("Tagged %s\n", tagged)
+	}
 }
 
 // config gets the flag environment set up, parses if we are showing version, about, or env.
 func config() {
 	// input actions
-	defaultInput := envVal(envDefaultInput, initialInputFile)
-	flag.StringVar(&inputFile, "in", defaultInput, fmt.Sprintf("input file (default: %s or BUMP_DEFAULT_INPUT)", initialInputFile))
+	check(loadSettings("."))
+	inputFiles.files = settingList("input")
+	flag.Var(&inputFiles, "in", fmt.Sprintf("input file, repeat to keep several files in lockstep (default: %s or BUMP_DEFAULT_INPUT)",
+		initialInputFile))
 	flag.StringVar(&shouldParse, "parse", "", "use value as input of new VERSION file")
 
 	// information actions
@@ -248,25 +569,82 @@ func config() {
 	flag.BoolVar(&major, "major", false, "major version bump")
 	flag.BoolVar(&minor, "minor", false, "minor version bump")
 	flag.BoolVar(&patch, "patch", false, "patch version bump")
+	flag.BoolVar(&revision, "revision", false, "revision (fourth component) version bump")
+	flag.BoolVar(&release, "release", false, "drop the pre-release (e.g. -SNAPSHOT) and build metadata")
+	flag.BoolVar(&promote, "promote", false, "advance the pre-release to the next channel (alpha → beta → rc → final)")
+	flag.StringVar(&channel, "channel", "", "move the pre-release to a channel of the ladder, starting at 1 (e.g. rc)")
+	flag.StringVar(&preLevel, "pre", settingVal("pre"), fmt.Sprintf("core bump of a pre-release of a final version: %s",
+		strings.Join(bump.Levels, ", ")))
+	flag.BoolVar(&downgrade, "allow-downgrade", false, "allow a bump whose result compares lower than the current version")
 	flag.BoolVar(&alpha, "alpha", false, "alpha version bump")
 	flag.BoolVar(&beta, "beta", false, "beta version bump")
 	flag.BoolVar(&rc, "rc", false, "rc version bump")
 	flag.BoolVar(&preview, "preview", false, "preview version bump")
+	flag.StringVar(&preRelease, "prerelease", "", "set dot-separated pre-release identifiers (e.g. nightly.20261017)")
+	flag.StringVar(&buildMeta, "build", "", "set dot-separated build metadata (e.g. 20261017.abc1234)")
+	flag.BoolVar(&buildGit, "build-git", false, "append the short git commit hash of HEAD to the build metadata")
+	flag.BoolVar(&buildDate, "build-date", false, "append the UTC date (YYYYMMDD) to the build metadata")
+	flag.BoolVar(&autoBump, "auto", false, "pick -major, -minor or -patch from the Conventional Commits since the last git tag")
+	flag.StringVar(&changelog, "changelog", settingVal("changelog"), "Keep a Changelog file that receives a section for the new version")
 
 	// flow control actions
-	flag.BoolVar(&useJson, "json", false, "use json output")
-	flag.BoolVar(&writeInput, "write", envIs(envAlwaysWrite), "write version back to file")
+	flag.BoolVar(&useJson, "json", settingIs("json"), "use json output")
+	flag.BoolVar(&descending, "desc", false, "sort in descending order")
+	flag.StringVar(&packageNames, "package", "", "workspace packages to bump by name or path (comma separated)")
+	flag.BoolVar(&changedOnly, "changed", false, "workspace packages whose files changed since their last git tag")
+	flag.StringVar(&versionRange, "range", "", "version range for satisfies (e.g. ^1.2, ~1.2.3, >=1.0.0 <2.0.0)")
+	flag.StringVar(&appVersion, "app-version", settingVal("app_version"),
+		fmt.Sprintf("Chart.yaml appVersion policy: %s", strings.Join(bump.AppVersionPolicies, ", ")))
+	flag.StringVar(&pomTarget, "pom-target", settingVal("pom_target"),
+		fmt.Sprintf("pom.xml version to use: %s", strings.Join(bump.PomTargets, ", ")))
+	flag.StringVar(&goSource, "go", settingVal("go"), fmt.Sprintf("go.mod Go version source: %s or a version such as 1.24.5",
+		strings.Join(bump.GoSources, ", ")))
+	flag.BoolVar(&goModule, "go-module", false, "with -major, migrate the go.mod module path and imports to the next /vN")
+	flag.BoolVar(&forceMajor, "force", false, "with -go-module, migrate a v0 module to /v2 instead of refusing it")
+	flag.StringVar(&dockerKey, "docker-key", settingVal("docker_key"), "Dockerfile KEY or LABEL:KEY, ARG:KEY, ENV:KEY to use as the version")
+	flag.BoolVar(&writeInput, "write", settingIs("always_write"), "write version back to file")
+	flag.StringVar(&versionFrom, "from", settingVal("from"), fmt.Sprintf("source of the version: %s or %s (highest tag reachable from HEAD)",
+		fromFile, fromGit))
+	flag.StringVar(&tagPrefix, "tag-prefix", settingVal("tag_prefix"), "prefix of the git tags of -from=git and -tag (e.g. v or mylib/v)")
+	flag.BoolVar(&writeTag, "write-tag", false, "create a git tag for the new version (same as -tag)")
+	flag.BoolVar(&writeTag, "tag", false, "create a git tag for the new version, annotated with -tag-message or lightweight when it is empty")
+	flag.StringVar(&tagMsg, "tag-message", settingVal("tag_message"), "template of the -tag message, such as Release {{.New}}")
+	flag.BoolVar(&commitBump, "commit", false, "write (as -write) and commit exactly the files that bump modified")
+	flag.StringVar(&commitMsg, "commit-message", settingVal("commit_message"), "template of the -commit message, such as chore(release): {{.Old}} → {{.New}}")
+	flag.BoolVar(&signRelease, "sign", settingIs("sign"), "sign the -commit and -tag using the local gpg or ssh signing config of git")
 	flag.BoolVar(&checkFile, "check", false, "check version file and print it")
-	flag.BoolVar(&shouldFix, "fix", envIs(envAlwaysFix), "fix malformed version string if possible")
-	flag.BoolVar(&shouldInit, "init", envIs(envInitOnNotFound), "initialize version file")
+	flag.BoolVar(&shouldFix, "fix", settingIs("always_fix"), "fix malformed version string if possible")
+	flag.BoolVar(&shouldInit, "init", settingIs("init_on_not_found"), "initialize version file")
 	flag.Parse()
+	applyFlags()
+	resolveInputFile()
+	bump.Ladder = settingList("ladder")
+	check(validList(bump.Levels)(preLevel))
+	if !slices.Contains([]string{fromFile, fromGit}, versionFrom) {
+		check(fmt.Errorf("invalid -from %q, expected %s or %s", versionFrom, fromFile, fromGit))
+	}
+	check(validTemplate(commitMsg))
+	check(validTemplate(tagMsg))
+	if signRelease && writeTag && len(tagMsg) == 0 {
+		check(errors.New("-sign needs a -tag-message, since a lightweight tag cannot be signed"))
+	}
+	if commitBump {
+		writeInput = true
+	}
+	if writeTag && !writeInput && versionFrom != fromGit {
+		check(fmt.Errorf("-tag needs -write or -commit, so that %s holds the tagged version", inputFile))
+	}
 
 	if showVersion {
 		fmt.Println(BinaryVersion())
 		os.Exit(0)
 	}
 	if showEnv {
-		fmt.Print(appEnv(""))
+		if useJson {
+			printJson(settings)
+		} else {
+			fmt.Print(appEnv(""))
+		}
 		os.Exit(0)
 	}
 	if showAbout {
@@ -274,13 +652,148 @@ func config() {
 		os.Exit(0)
 	}
 
-	// Load env-controlled settings
-	if envIs(envNeverFix) {
+	// Load env and config controlled settings
+	if settingIs("never_fix") {
 		shouldFix = false
 	}
 
 }
 
+// allowed reports whether the pre-release channel can be bumped according to the channels and no_* settings
+func allowed(channel string) bool {
+	switch channel {
+	case "alpha":
+		if settingIs("no_alpha") || settingIs("no_alpha_beta") {
+			return false
+		}
+	case "beta":
+		if settingIs("no_beta") || settingIs("no_alpha_beta") {
+			return false
+		}
+	case "rc":
+		if settingIs("no_rc") {
+			return false
+		}
+	case "preview":
+		if settingIs("no_preview") {
+			return false
+		}
+	}
+	return slices.Contains(settingList("channels"), channel)
+}
+
+// save runs the hooks.pre setting, writes the version to every -in file (and the -changelog file) in a single
+// transaction and runs the hooks.post setting
+func save(version *bump.Version, originalVersion, newVersion string) {
+	check(runHook("hooks.pre", originalVersion, newVersion))
+	files, err := bump.RenderAll(append([]*bump.Version{version}, linked...)...)
+	check(err)
+	if changelogContent != nil {
+		files[changelog] = changelogContent
+	}
+	check(bump.WriteFiles(files))
+	changelogSaved = changelogContent != nil
+	saved = slices.Sorted(maps.Keys(files))
+	check(runHook("hooks.post", originalVersion, newVersion))
+	if commitBump {
+		var err error
+		commit, err = bump.GitCommit(filepath.Dir(inputFile), releaseMessage(commitMsg, originalVersion, newVersion),
+			absPaths(saved), signRelease)
+		check(err)
+	}
+}
+
+// checkStaged refuses to -commit when the index of the git repository has staged changes to other files than the ones
+// that save writes for version, since they would end up in the release commit
+func checkStaged(version *bump.Version) {
+	files, err := bump.RenderAll(append([]*bump.Version{version}, linked...)...)
+	check(err)
+	if len(changelog) > 0 {
+		files[changelog] = nil
+	}
+	written := make(map[string]bool, len(files))
+	for _, p := range absPaths(slices.Collect(maps.Keys(files))) {
+		written[p] = true
+	}
+	staged, err := bump.GitStaged(filepath.Dir(inputFile))
+	check(err)
+	for _, p := range absPaths(staged) {
+		if !written[p] {
+			check(fmt.Errorf("-commit refuses to run with unrelated staged changes to %s", p))
+		}
+	}
+}
+
+// absPaths returns the absolute paths of paths with the symbolic links of their directories resolved, so that they
+// compare equal to the paths reported by git
+func absPaths(paths []string) []string {
+	result := make([]string, 0, len(paths))
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		check(err)
+		if dir, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+			abs = filepath.Join(dir, filepath.Base(abs))
+		}
+		result = append(result, abs)
+	}
+	return result
+}
+
+// createTag creates the -tag of the new version on HEAD once, after save has written and committed the files, and
+// records it in tagged
+func createTag(originalVersion, newVersion string) {
+	if !writeTag || len(tagged) > 0 {
+		return
+	}
+	message := releaseMessage(tagMsg, originalVersion, newVersion)
+	check(bump.GitTag(filepath.Dir(inputFile), newTag, message, signRelease))
+	tagged = newTag
+}
+
+// releaseMessage renders the -commit-message or -tag-message template with the versions, tag and saved files
+func releaseMessage(text, originalVersion, newVersion string) string {
+	if len(text) == 0 {
+		return ""
+	}
+	var out strings.Builder
+	tmpl := template.Must(template.New("message").Option("missingkey=error").Parse(text))
+	check(tmpl.Execute(&out, releaseData{Old: originalVersion, New: newVersion, Tag: newTag, Files: saved}))
+	return out.String()
+}
+
+// validTemplate validates a -commit-message or -tag-message template
+func validTemplate(text string) error {
+	_, err := template.New("message").Parse(text)
+	return err
+}
+
+// firstLine returns the first line of text
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
+
+// runHook runs the shell command of the hook setting with BUMP_OLD_VERSION, BUMP_NEW_VERSION, BUMP_FILE and BUMP_FILES
+// in its environment, sending its output to STDERR so that -json output stays parseable
+func runHook(name, originalVersion, newVersion string) error {
+	command := settingVal(name)
+	if len(command) == 0 {
+		return nil
+	}
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Env = append(os.Environ(),
+		"BUMP_OLD_VERSION="+originalVersion,
+		"BUMP_NEW_VERSION="+newVersion,
+		"BUMP_FILE="+inputFile,
+		"BUMP_FILES="+inputFiles.String(),
+	)
+	cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s hook %q: %w", name, command, err)
+	}
+	return nil
+}
+
 // validate attempts to count the number of bump commands being executed
 func validate() (int, error) {
 	bumpFlags := 0
@@ -293,35 +806,68 @@ func validate() (int, error) {
 	if patch {
 		bumpFlags++
 	}
+	if revision {
+		bumpFlags++
+	}
+	if release {
+		bumpFlags++
+	}
+	if autoBump {
+		bumpFlags++
+	}
+	if promote {
+		bumpFlags++
+	}
 	// Pre-release bumps can be combined with major/minor/patch, but not with each other.
 	preReleaseFlags := 0
-	if alpha && !envIs(envNoAlpha) && !envIs(envNoAlphaBeta) {
+	if alpha && allowed("alpha") {
 		preReleaseFlags++
 	}
-	if beta && !envIs(envNoBeta) && !envIs(envNoAlphaBeta) {
+	if beta && allowed("beta") {
 		preReleaseFlags++
 	}
-	if rc && !envIs(envNoRC) {
+	if rc && allowed("rc") {
 		preReleaseFlags++
 	}
-	if preview && !envIs(envNoPreview) {
+	if preview && allowed("preview") {
 		preReleaseFlags++
 	}
+	if len(channel) > 0 {
+		if !slices.Contains(bump.Ladder, channel) {
+			return 0, fmt.Errorf("-channel %s is not one of the channels %s", channel, strings.Join(bump.Ladder, ", "))
+		}
+		if !slices.Contains(bump.Channels, channel) || allowed(channel) {
+			preReleaseFlags++
+		}
+	}
 
 	if bumpFlags > 1 {
-		return 0, fmt.Errorf("only one of -major, -minor, or -patch can be used at a time")
+		return 0, fmt.Errorf("only one of -major, -minor, -patch, -revision, -release, -promote or -auto can be used at a time")
 	}
 	if preReleaseFlags > 1 {
 		// Exception: allow alpha and beta to be combined
 		if !(preReleaseFlags == 2 && (alpha && beta)) {
-			return 0, fmt.Errorf("only one pre-release bump can be used at a time (e.g., -alpha, -beta)")
+			return 0, fmt.Errorf("only one pre-release bump can be used at a time (e.g., -alpha, -beta, -channel)")
 		}
 	}
 	return bumpFlags + preReleaseFlags, nil
 }
 
-// run executes the bump commands using the bump package
+// run executes the bump commands using the bump package; a pre-release of a final version without a core bump first
+// bumps the core version by -pre, since the pre-release would otherwise sort before the final version
 func run(version *bump.Version) {
+	core := major || minor || patch || revision
+	if !core && !release && !promote && startsPreRelease() && len(version.Channel()) == 0 {
+		switch preLevel {
+		case bump.LevelMajor:
+			version.BumpMajor()
+		case bump.LevelMinor:
+			version.BumpMinor()
+		case bump.LevelPatch:
+			version.BumpPatch()
+		}
+		core = preLevel != bump.LevelNone
+	}
 	if major {
 		version.BumpMajor()
 	}
@@ -331,27 +877,174 @@ func run(version *bump.Version) {
 	if patch {
 		version.BumpPatch()
 	}
-	if rc && !envIs(envNoRC) {
+	if revision {
+		version.BumpRevision()
+	}
+	if release {
+		version.Release()
+	}
+	if promote {
+		check(version.Promote())
+	}
+	if len(channel) > 0 && (!slices.Contains(bump.Channels, channel) || allowed(channel)) {
+		if core {
+			check(version.SetPreRelease(channel + ".1")) // the new core version has no channel to move from
+		} else {
+			check(version.SetChannel(channel))
+		}
+	}
+	if rc && allowed("rc") {
 		version.BumpRC()
 	}
-	if beta && !envIs(envNoBeta) && !envIs(envNoAlphaBeta) {
+	if beta && allowed("beta") {
 		version.BumpBeta()
 	}
-	if alpha && !envIs(envNoAlpha) && !envIs(envNoAlphaBeta) {
+	if alpha && allowed("alpha") {
 		version.BumpAlpha()
 	}
-	if preview && !envIs(envNoPreview) {
+	if preview && allowed("preview") {
 		version.BumpPreview()
 	}
 }
 
+// startsPreRelease reports whether a pre-release bump (-alpha, -beta, -rc, -preview, -channel or -prerelease) is requested
+func startsPreRelease() bool {
+	return (alpha && allowed("alpha")) || (beta && allowed("beta")) || (rc && allowed("rc")) || (preview && allowed("preview")) ||
+		(len(channel) > 0 && (!slices.Contains(bump.Channels, channel) || allowed(channel))) || len(preRelease) > 0
+}
+
+// snapshot returns a copy of the version of v before it is bumped, rendered with the "v" prefix of v
+func snapshot(v *bump.Version) *bump.Version {
+	s := bump.New()
+	s.Assign(v)
+	s.SetNoPrefix(v.NoPrefix())
+	return s
+}
+
+// refuseDowngrade returns an error when the bumped version compares lower than the version before the bump, unless
+// -allow-downgrade is set
+func refuseDowngrade(before, version *bump.Version) error {
+	if downgrade || version.Compare(before) >= 0 {
+		return nil
+	}
+	return fmt.Errorf("%s → %s is a downgrade, use -allow-downgrade to allow it", before.Format(!before.NoPrefix()),
+		version.Format(!version.NoPrefix()))
+}
+
+// migrateGoModule uses bump.MigrateGoModule on the -in go.mod for -major and prints the module path change and the files
+// it rewrites, saving them with -write; the highest -tag-prefix tag tells a v0 module apart, which needs -force
+func migrateGoModule() {
+	bumpFlags, err := validate()
+	check(err)
+	if !major || bumpFlags != 1 {
+		check(errors.New("-go-module only supports -major, since only a major version changes a Go module path"))
+	}
+	if filepath.Base(inputFile) != bump.FileGoMod {
+		check(fmt.Errorf("-go-module requires -in to be a %s file", bump.FileGoMod))
+	}
+	_, latest, err := bump.GitLatestTag(filepath.Dir(inputFile), tagPrefix)
+	if err != nil {
+		latest = nil // not a git repository, so the released version is unknown
+	}
+	m, err := bump.MigrateGoModule(inputFile, latest, forceMajor)
+	check(err)
+	if len(m.Warning) > 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "Warning:", m.Warning)
+	}
+	if writeInput {
+		check(m.Write())
+	}
+	if useJson {
+		printJson(m)
+		return
+	}
+	if writeInput {
+		fmt.Printf("Migrated %s → %s (saved %d files)\n", m.OldPath, m.NewPath, len(m.Files))
+	} else {
+		fmt.Printf("Migrate %s → %s (%d files, use -write to save)\n", m.OldPath, m.NewPath, len(m.Files))
+	}
+	for _, f := range m.Files {
+		fmt.Printf("  %s\n", f)
+	}
+}
+
+// autoLevel classifies the commits since the highest -tag-prefix tag reachable from HEAD (every commit when there is
+// none) per Conventional Commits and sets -major, -minor or -patch to the level they imply
+func autoLevel(version *bump.Version) {
+	dir := filepath.Dir(inputFile)
+	since, _, err := bump.GitLatestTag(dir, tagPrefix)
+	check(err)
+	commits, err := bump.GitCommits(dir, since, commitTypes(), version)
+	check(err)
+	level, drivers = bump.CommitLevel(commits)
+	switch level {
+	case bump.LevelMajor:
+		major = true
+	case bump.LevelMinor:
+		minor = true
+	case bump.LevelPatch:
+		patch = true
+	}
+}
+
+// commitTypes returns the type=level pairs of the commit_types setting as a map
+func commitTypes() map[string]string {
+	types := make(map[string]string)
+	for _, pair := range settingList("commit_types") {
+		t, l, _ := strings.Cut(pair, "=")
+		types[strings.TrimSpace(t)] = strings.TrimSpace(l)
+	}
+	return types
+}
+
+// promoteChangelog moves the [Unreleased] notes of the -changelog file under a section of the new version, generating
+// them from the commits since the highest -tag-prefix tag reachable from HEAD when there are none
+func promoteChangelog(version *bump.Version) {
+	content, err := os.ReadFile(changelog)
+	if err != nil && !os.IsNotExist(err) {
+		check(err)
+	}
+	dir := filepath.Dir(inputFile)
+	since, _, err := bump.GitLatestTag(dir, tagPrefix)
+	check(err)
+	commits, err := bump.GitCommits(dir, since, commitTypes(), version)
+	check(err)
+	r := bump.Release{
+		Version: strings.TrimPrefix(version.Format(false), "v"),
+		Tag:     tagName(version),
+		Date:    time.Now(),
+		Commits: commits,
+	}
+	changelogContent, changelogSection, err = bump.PromoteChangelog(content, r)
+	check(err)
+}
+
+// buildMetadata joins -build, -build-date and -build-git into the dot-separated build metadata of the new version
+func buildMetadata() string {
+	var parts []string
+	if len(buildMeta) > 0 {
+		parts = append(parts, strings.TrimPrefix(buildMeta, "+"))
+	}
+	if buildDate {
+		parts = append(parts, time.Now().UTC().Format("20060102"))
+	}
+	if buildGit {
+		sha, err := bump.GitShortCommit(filepath.Dir(inputFile))
+		check(err)
+		parts = append(parts, sha)
+	}
+	return strings.Join(parts, ".")
+}
+
 // finish prints the summary output of the bump request
 func finish(version *bump.Version, wasBumped bool, bumpFlags int, originalVersion, newVersion string) {
 	if useJson {
-		printJson(version)
 		if writeInput {
-			check(version.Save(inputFile))
+			save(version, originalVersion, newVersion)
 		}
+		createTag(originalVersion, newVersion)
+		printJson(&bumpResult{Version: version, Files: touched, Tag: tagged, Level: level, Commits: drivers,
+			Changelog: changelogSection, Commit: commit})
 		return
 	}
 
@@ -362,28 +1055,30 @@ func finish(version *bump.Version, wasBumped bool, bumpFlags int, originalVersio
 
 	if shouldInit && strings.EqualFold(originalVersion, newVersion) {
 		if writeInput {
-			check(version.Save(inputFile))
-			fmt.Printf("Initialized %s (saved to %s)\n", originalVersion, inputFile)
+			save(version, originalVersion, newVersion)
+			fmt.Printf("Initialized %s (saved to %s)\n", originalVersion, savedTo())
 		} else {
 			fmt.Printf("Initialized %s\n", originalVersion)
 		}
 	} else if wasBumped {
 		if writeInput {
-			check(version.Save(inputFile))
+			save(version, originalVersion, newVersion)
 			if strings.EqualFold(originalVersion, newVersion) && len(shouldParse) > 0 {
-				fmt.Printf("Parsed %s (saved to %s)\n", newVersion, inputFile)
+				fmt.Printf("Parsed %s (saved to %s)\n", newVersion, savedTo())
 			} else {
-				fmt.Printf("Bumped %s → %s (saved to %s)\n", originalVersion, newVersion, inputFile)
+				fmt.Printf("Bumped %s → %s (saved to %s)\n", originalVersion, newVersion, savedTo())
 			}
 		} else {
 			fmt.Printf("Bumped %s → %s\n", originalVersion, newVersion)
 		}
+		printTouched()
 	} else if writeInput && shouldFix {
-		check(version.Save(inputFile))
-		fmt.Printf("Fixed and saved version %s to %s\n", newVersion, inputFile)
+		save(version, originalVersion, newVersion)
+		fmt.Printf("Fixed and saved version %s to %s\n", newVersion, savedTo())
+		printTouched()
 	} else if bumpFlags == 0 && !checkFile {
 		if len(shouldParse) > 0 && !strings.EqualFold(originalVersion, newVersion) {
-			check(version.Save(inputFile))
+			save(version, originalVersion, newVersion)
 		} else {
 			fmt.Println("No bump operation specified. Use -major, -minor, -patch, etc., to bump the version.")
 		}
@@ -396,9 +1091,10 @@ func finish(version *bump.Version, wasBumped bool, bumpFlags int, originalVersio
 
 // printJson uses check() on the error and prints to STDOUT the Indented JSON output
 func printJson(data interface{}) {
-	output, err := json.MarshalIndent(data, "", "  ")
-	check(err)
-	fmt.Println(string(output))
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false) // ranges like >=1.0.0 <2.0.0 should print as written
+	encoder.SetIndent("", "  ")
+	check(encoder.Encode(data))
 }
 
 // check a variable assigned a func type can be redefined but falls through the logic when an err
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/andreimerlescu/bump/bump"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	sourceDefault    = "default"     // setting uses its built-in default
+	sourceUserConfig = "user config" // setting comes from the config file of the user
+	sourceRepoConfig = "repo config" // setting comes from the .bump.yaml (or .bump.toml) of the repository
+	sourceEnv        = "env"         // setting comes from its BUMP_* environment variable
+	sourceFlag       = "flag"        // setting comes from its command line flag
+)
+
+// reChannel matches a channel of the ladder, which is a pre-release identifier that is not numeric
+var reChannel = regexp.MustCompile(`^[0-9A-Za-z-]*[A-Za-z-][0-9A-Za-z-]*$`)
+
+// configFiles are the names of the repository config file, discovered from the working directory upward
+var configFiles = []string{".bump.yaml", ".bump.yml", ".bump.toml"}
+
+// setting is a configurable default of bump, resolved using the precedence flags > env > repo config > user config >
+// defaults
+type setting struct {
+	Name   string `json:"name"`           // key in .bump.yaml or .bump.toml, such as always_write or hooks.pre
+	Env    string `json:"env"`            // environment variable, such as BUMP_ALWAYS_WRITE
+	Flag   string `json:"flag,omitempty"` // command line flag that overrides the setting, such as write
+	Value  string `json:"value"`          // effective value, lists are comma separated
+	Source string `json:"source"`         // where Value came from, such as env or repo config (/repo/.bump.yaml)
+
+	valid func(value string) error // validates a value before it is applied
+}
+
+// settings are every configurable default of bump in the order that -env prints them
+var settings = []*setting{
+	{Name: "input", Env: envDefaultInput, Flag: "in", Value: initialInputFile},
+	{Name: "always_write", Env: envAlwaysWrite, Flag: "write", Value: "false", valid: validBool},
+	{Name: "always_fix", Env: envAlwaysFix, Flag: "fix", Value: "false", valid: validBool},
+	{Name: "never_fix", Env: envNeverFix, Value: "false", valid: validBool},
+	{Name: "init_on_not_found", Env: envInitOnNotFound, Flag: "init", Value: "false", valid: validBool},
+	{Name: "no_alpha", Env: envNoAlpha, Value: "false", valid: validBool},
+	{Name: "no_beta", Env: envNoBeta, Value: "false", valid: validBool},
+	{Name: "no_alpha_beta", Env: envNoAlphaBeta, Value: "false", valid: validBool},
+	{Name: "no_rc", Env: envNoRC, Value: "false", valid: validBool},
+	{Name: "no_preview", Env: envNoPreview, Value: "false", valid: validBool},
+	{Name: "channels", Env: envChannels, Value: strings.Join(bump.Channels, ","), valid: validList(bump.Channels)},
+	{Name: "ladder", Env: envLadder, Value: strings.Join(bump.Ladder, ","), valid: validLadder},
+	{Name: "pre", Env: envPre, Flag: "pre", Value: bump.LevelPatch, valid: validList(bump.Levels)},
+	{Name: "prefix", Env: envPrefix, Value: prefixKeep, valid: validList([]string{prefixKeep, prefixAlways, prefixNever})},
+	{Name: "json", Env: envJson, Flag: "json", Value: "false", valid: validBool},
+	{Name: "app_version", Env: envAppVersion, Flag: "app-version", Value: bump.AppVersionIgnore},
+	{Name: "pom_target", Env: envPomTarget, Flag: "pom-target", Value: bump.PomProject},
+	{Name: "docker_key", Env: envDockerKey, Flag: "docker-key"},
+	{Name: "go", Env: envGoSource, Flag: "go", Value: bump.GoSourceAuto},
+	{Name: "from", Env: envFrom, Flag: "from", Value: fromFile, valid: validList([]string{fromFile, fromGit})},
+	{Name: "tag_prefix", Env: envTagPrefix, Flag: "tag-prefix", Value: "v"},
+	{Name: "commit_types", Env: envCommitTypes, Value: "feat=minor,fix=patch,perf=patch", valid: validCommitTypes},
+	{Name: "changelog", Env: envChangelog, Flag: "changelog"},
+	{Name: "commit_message", Env: envCommitMessage, Flag: "commit-message", Value: "chore(release): {{.Old}} → {{.New}}", valid: validTemplate},
+	{Name: "tag_message", Env: envTagMessage, Flag: "tag-message", Value: "Release {{.New}}", valid: validTemplate},
+	{Name: "sign", Env: envSign, Flag: "sign", Value: "false", valid: validBool},
+	{Name: "hooks.pre", Env: envPreHook},
+	{Name: "hooks.post", Env: envPostHook},
+}
+
+// loadSettings resolves every setting from the user config, the repository config discovered from dir upward and the
+// environment, where each layer overrides the one before it
+func loadSettings(dir string) error {
+	for _, s := range settings {
+		s.Source = sourceDefault
+	}
+	if path, ok := userConfig(); ok {
+		if err := applyConfig(path, sourceUserConfig); err != nil {
+			return err
+		}
+	}
+	if path, ok := discoverConfig(dir); ok {
+		if err := applyConfig(path, sourceRepoConfig); err != nil {
+			return err
+		}
+	}
+	for _, s := range settings {
+		if value, ok := os.LookupEnv(s.Env); ok {
+			if err := s.set(value, sourceEnv); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyFlags marks the settings whose flag was passed on the command line with their flag value
+func applyFlags() {
+	flag.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.Flag == f.Name {
+				s.Value, s.Source = f.Value.String(), sourceFlag
+			}
+		}
+	})
+}
+
+// set validates and applies value to the setting from source
+func (s *setting) set(value, source string) error {
+	if s.valid != nil {
+		if err := s.valid(value); err != nil {
+			return fmt.Errorf("invalid %s from %s: %w", s.Name, source, err)
+		}
+	}
+	s.Value, s.Source = value, source
+	return nil
+}
+
+// lookupSetting returns the setting with name, panicking on an unknown name since that is a programming error
+func lookupSetting(name string) *setting {
+	for _, s := range settings {
+		if s.Name == name {
+			return s
+		}
+	}
+	panic("unknown setting " + name)
+}
+
+// settingVal returns the effective value of the setting with name
+func settingVal(name string) string {
+	return lookupSetting(name).Value
+}
+
+// settingIs returns the effective value of the boolean setting with name
+func settingIs(name string) bool {
+	b, err := strconv.ParseBool(settingVal(name))
+	return err == nil && b
+}
+
+// settingList returns the effective comma separated values of the setting with name
+func settingList(name string) []string {
+	var values []string
+	for _, v := range strings.Split(settingVal(name), ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// discoverConfig returns the path of the first repository config file found in dir or any of its parents
+func discoverConfig(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		for _, name := range configFiles {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path, true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// userConfig returns the path of the config file of the user inside os.UserConfigDir, such as ~/.config/bump/config.yaml
+func userConfig() (string, bool) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", false
+	}
+	for _, name := range []string{"config.yaml", "config.yml", "config.toml"} {
+		path := filepath.Join(dir, "bump", name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// applyConfig reads the config file at path and applies its values to the settings, resolving relative input and
+// changelog files against the directory of the config file
+func applyConfig(path, source string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var values map[string]string
+	if strings.HasSuffix(path, ".toml") {
+		values, err = readTomlConfig(content)
+	} else {
+		values, err = readYamlConfig(content)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	source = fmt.Sprintf("%s (%s)", source, path)
+	for _, s := range settings {
+		value, ok := values[s.Name]
+		if !ok {
+			continue
+		}
+		delete(values, s.Name)
+		if s.Name == "input" || s.Name == "changelog" {
+			value = resolveInputs(value, filepath.Dir(path))
+		}
+		if err := s.set(value, source); err != nil {
+			return err
+		}
+	}
+	for name := range values {
+		return fmt.Errorf("unknown setting %q in config %s", name, path)
+	}
+	return nil
+}
+
+// resolveInputs resolves the comma separated input files of value that are relative against dir
+func resolveInputs(value, dir string) string {
+	var files []string
+	for _, f := range strings.Split(value, ",") {
+		if f = strings.TrimSpace(f); len(f) == 0 {
+			continue
+		}
+		if !filepath.IsAbs(f) {
+			f = filepath.Join(dir, f)
+		}
+		files = append(files, f)
+	}
+	return strings.Join(files, ",")
+}
+
+// readYamlConfig flattens a YAML config into dotted keys (hooks.pre) with comma separated lists
+func readYamlConfig(content []byte) (map[string]string, error) {
+	m := make(map[string]any)
+	if err := yaml.Unmarshal(content, &m); err != nil {
+		return nil, err
+	}
+	values := make(map[string]string)
+	var flatten func(prefix string, m map[string]any)
+	flatten = func(prefix string, m map[string]any) {
+		for k, v := range m {
+			switch t := v.(type) {
+			case map[string]any:
+				flatten(prefix+k+".", t)
+			case []any:
+				items := make([]string, 0, len(t))
+				for _, item := range t {
+					items = append(items, fmt.Sprint(item))
+				}
+				values[prefix+k] = strings.Join(items, ",")
+			case nil:
+				values[prefix+k] = ""
+			default:
+				values[prefix+k] = fmt.Sprint(t)
+			}
+		}
+	}
+	flatten("", m)
+	return values, nil
+}
+
+// readTomlConfig reads the key = value pairs and [table] headers of a TOML config into dotted keys (hooks.pre), where
+// values are strings, booleans, numbers or arrays of strings
+func readTomlConfig(content []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	table := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			end := strings.Index(line, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: invalid table header %q", n, line)
+			}
+			table = strings.TrimSpace(line[1:end]) + "."
+			continue
+		}
+		key, raw, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", n)
+		}
+		value, err := tomlConfigValue(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		values[table+strings.Trim(strings.TrimSpace(key), `"'`)] = value
+	}
+	return values, scanner.Err()
+}
+
+// tomlConfigValue converts a TOML string, boolean, number or array of strings into its setting value
+func tomlConfigValue(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		end := strings.LastIndex(raw, `"`)
+		if end <= 0 {
+			return "", fmt.Errorf("unterminated string %s", raw)
+		}
+		return strconv.Unquote(raw[:end+1])
+	case strings.HasPrefix(raw, "'"):
+		end := strings.LastIndex(raw, "'")
+		if end <= 0 {
+			return "", fmt.Errorf("unterminated string %s", raw)
+		}
+		return raw[1:end], nil
+	case strings.HasPrefix(raw, "["):
+		end := strings.LastIndex(raw, "]")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated array %s", raw)
+		}
+		var items []string
+		for _, item := range strings.Split(raw[1:end], ",") {
+			if item = strings.TrimSpace(item); len(item) == 0 {
+				continue
+			}
+			value, err := tomlConfigValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, value)
+		}
+		return strings.Join(items, ","), nil
+	}
+	if i := strings.Index(raw, "#"); i >= 0 {
+		raw = strings.TrimSpace(raw[:i])
+	}
+	if len(raw) == 0 {
+		return "", errors.New("missing value")
+	}
+	return raw, nil
+}
+
+// validBool validates a boolean setting value
+func validBool(value string) error {
+	_, err := strconv.ParseBool(value)
+	return err
+}
+
+// validCommitTypes validates comma separated type=level pairs, where level is one of bump.Levels
+func validCommitTypes(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); len(pair) == 0 {
+			continue
+		}
+		t, l, ok := strings.Cut(pair, "=")
+		if !ok || len(strings.TrimSpace(t)) == 0 {
+			return fmt.Errorf("%q is not a type=level pair", pair)
+		}
+		if !slices.Contains(bump.Levels, strings.TrimSpace(l)) {
+			return fmt.Errorf("%q is not one of %s", strings.TrimSpace(l), strings.Join(bump.Levels, ", "))
+		}
+	}
+	return nil
+}
+
+// validLadder validates the comma separated channels of the ladder, which must be alphanumeric pre-release identifiers
+func validLadder(value string) error {
+	for _, c := range strings.Split(value, ",") {
+		if c = strings.TrimSpace(c); len(c) > 0 && !reChannel.MatchString(c) {
+			return fmt.Errorf("%q is not an alphanumeric pre-release identifier", c)
+		}
+	}
+	return nil
+}
+
+// validList returns a validator of comma separated values that must each be one of allowed
+func validList(allowed []string) func(string) error {
+	return func(value string) error {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); len(v) > 0 && !slices.Contains(allowed, v) {
+				return fmt.Errorf("%q is not one of %s", v, strings.Join(allowed, ", "))
+			}
+		}
+		return nil
+	}
+}
@@ -51,6 +51,26 @@ declare -a tests=(
  "${scenario_12[@]}"
  "${scenario_13[@]}"
  "${scenario_14[@]}"
+ "${scenario_15[@]}"
+ "${scenario_16[@]}"
+ "${scenario_17[@]}"
+ "${scenario_18[@]}"
+ "${scenario_19[@]}"
+ "${scenario_20[@]}"
+ "${scenario_21[@]}"
+ "${scenario_22[@]}"
+ "${scenario_23[@]}"
+ "${scenario_24[@]}"
+ "${scenario_25[@]}"
+ "${scenario_26[@]}"
+ "${scenario_27[@]}"
+ "${scenario_28[@]}"
+ "${scenario_29[@]}"
+ "${scenario_30[@]}"
+ "${scenario_31[@]}"
+ "${scenario_32[@]}"
+ "${scenario_33[@]}"
+ "${scenario_34[@]}"
 )
 
 for t in "${tests[@]}"; do
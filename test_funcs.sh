@@ -82,6 +82,26 @@ function cleanup() {
   unset scenario_12
   unset scenario_13
   unset scenario_14
+  unset scenario_15
+  unset scenario_16
+  unset scenario_17
+  unset scenario_18
+  unset scenario_19
+  unset scenario_20
+  unset scenario_21
+  unset scenario_22
+  unset scenario_23
+  unset scenario_24
+  unset scenario_25
+  unset scenario_26
+  unset scenario_27
+  unset scenario_28
+  unset scenario_29
+  unset scenario_30
+  unset scenario_31
+  unset scenario_32
+  unset scenario_33
+  unset scenario_34
   unset tests
   unset populated_package_json
   unset empty_chart_yaml
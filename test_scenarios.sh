@@ -15,7 +15,7 @@ declare -a scenario_01=(
   "cat VERSION"
   "bump -alpha -write"
   "cat VERSION"
-  "grep 'v1.0.0-alpha.1' VERSION"
+  "grep 'v1.0.1-alpha.1' VERSION"
   "rm VERSION"
 )
 
@@ -41,7 +41,7 @@ declare -a scenario_02=(
   "cat VERSION"
   "grep 'v2.0.0' VERSION"
   "bump -preview -write"
-  "grep 'v2.0.0-preview.1' VERSION"
+  "grep 'v2.0.1-preview.1' VERSION"
   "rm VERSION"
 )
 
@@ -100,10 +100,10 @@ declare -a scenario_07=(
   "bump -in package.json -fix -write"
   "bump -in package.json -patch"
   "bump -in package.json -patch -write"
-  "grep '\"version\": \"1.2.4\"' package.json"
+  "grep '\"version\": *\"1.2.4\"' package.json"
   "bump -in package.json -json -minor"
   "bump -in package.json -minor -write"
-  "grep '\"version\": \"1.3.0\"' package.json"
+  "grep '\"version\": *\"1.3.0\"' package.json"
   "rm package.json"
 )
 
@@ -193,6 +193,284 @@ declare -a scenario_14=(
   "rm package.json"
 )
 
+# compare, sort, max and min versions using bump's own precedence
+declare -a scenario_15=(
+  "bump compare v1.0.0 1.0.0"
+  "bump compare v1.0.0-rc.1 v1.0.0-alpha.5; [ \$? -eq 1 ]"
+  "bump compare -json v1.0.0-beta v1.0.0; [ \$? -eq 2 ]"
+  "bump sort 1.0.0 1.0.0-rc.1 1.0.0-beta.11 1.0.0-beta.2 | head -n 1 | grep '1.0.0-beta.2'"
+  "printf 'v2.0.0\nv1.0.0\nv2.0.0-rc.1\n' | bump sort -desc | head -n 1 | grep 'v2.0.0'"
+  "bump max v1.0.0 v2.0.0-alpha.1 v1.9.9 | grep 'v2.0.0-alpha.1'"
+  "bump min v1.0.0 v1.0.0-alpha.1 v1.9.9 | grep 'v1.0.0-alpha.1'"
+)
+
+# gate on version ranges with satisfies
+declare -a scenario_16=(
+  "echo \"v1.4.2\" > VERSION"
+  "bump satisfies -range='^1.2'"
+  "bump satisfies -range='>=2.0.0 <3.0.0'; [ \$? -eq 1 ]"
+  "bump satisfies -range='~1.4' -json | grep '\"satisfies\": true'"
+  "bump satisfies -range='1.2.3 - 2' 2.9.9 1.2.3"
+  "echo '${empty_package_json}' | base64 -d | tee package.json > /dev/null"
+  "bump satisfies -range='1.x' -in=package.json"
+  "rm VERSION package.json"
+)
+
+# bump the [package] version of a Cargo.toml without reformatting it
+declare -a scenario_17=(
+  "printf '[package]\nname = \"crate\" # comment\nversion = \"0.3.9\"\n\n[dependencies]\nserde = \"1\"\n' > Cargo.toml"
+  "bump -in Cargo.toml -check | grep '0.3.9'"
+  "bump -in Cargo.toml -minor -write"
+  "grep 'version = \"0.4.0\"' Cargo.toml"
+  "grep 'name = \"crate\" # comment' Cargo.toml"
+  "rm Cargo.toml"
+)
+
+# bump python packages using PEP 440 spellings
+declare -a scenario_18=(
+  "printf '[project]\nname = \"pkg\"\nversion = \"1.2.3rc1\"\n' > pyproject.toml"
+  "bump -in pyproject.toml -check | grep '1.2.3rc1'"
+  "bump -in pyproject.toml -rc -write"
+  "grep 'version = \"1.2.3rc2\"' pyproject.toml"
+  "printf '__version__ = \"0.1.0\"\n' > __init__.py"
+  "bump -in __init__.py -minor -write"
+  "grep '__version__ = \"0.2.0\"' __init__.py"
+  "rm pyproject.toml __init__.py"
+)
+
+# Scenario 19: Gradle and .NET project files
+declare -a scenario_19=(
+  "printf 'group=com.example\nversion=1.4.0\n' > gradle.properties"
+  "bump -in gradle.properties -minor -write"
+  "grep '^version=1.5.0$' gradle.properties"
+  "printf '<Project>\n  <PropertyGroup>\n    <Version>1.2.3.4</Version>\n  </PropertyGroup>\n</Project>\n' > App.csproj"
+  "bump -in App.csproj -revision -write"
+  "grep '<Version>1.2.3.5</Version>' App.csproj"
+  "rm gradle.properties App.csproj"
+)
+
+# Scenario 20: Chart.yaml appVersion policies
+declare -a scenario_20=(
+  "printf 'name: api\nversion: 1.2.3\nappVersion: \"v4.0.0\"\n' > Chart.yaml"
+  "bump -in Chart.yaml -app-version=only -check | grep 'v4.0.0'"
+  "bump -in Chart.yaml -app-version=sync -minor -write"
+  "grep 'appVersion: \"v1.3.0\"' Chart.yaml"
+  "BUMP_APP_VERSION=derive bump -in Chart.yaml -patch -write"
+  "grep 'version: 1.3.1' Chart.yaml"
+  "grep 'appVersion: \"v1.3.1\"' Chart.yaml"
+  "rm Chart.yaml"
+)
+
+# Scenario 21: pom.xml parent, reactor and -SNAPSHOT release
+declare -a scenario_21=(
+  "mkdir -p core"
+  "printf '<project>\n  <parent><groupId>org.base</groupId><artifactId>base</artifactId><version>7.0.0</version></parent>\n  <groupId>com.example</groupId>\n  <artifactId>app</artifactId>\n  <version>1.4.0-SNAPSHOT</version>\n  <modules><module>core</module></modules>\n</project>\n' > pom.xml"
+  "printf '<project>\n  <parent><groupId>com.example</groupId><artifactId>app</artifactId><version>1.4.0-SNAPSHOT</version></parent>\n</project>\n' > core/pom.xml"
+  "bump -in pom.xml -check | grep '1.4.0-SNAPSHOT'"
+  "bump -in pom.xml -pom-target=parent -check | grep '7.0.0'"
+  "bump -in pom.xml -release -write"
+  "grep '<version>1.4.0</version>' pom.xml"
+  "grep '<version>1.4.0</version>' core/pom.xml"
+  "bump -in pom.xml -minor -prerelease=SNAPSHOT -write"
+  "grep '<version>1.5.0-SNAPSHOT</version>' core/pom.xml"
+  "rm -r pom.xml core"
+)
+
+# Scenario 22: Dockerfile ARG, ENV and OCI labels
+declare -a scenario_22=(
+  "printf 'ARG VERSION=2.0.0\nFROM alpine\nENV APP_VERSION=2.0.0\nLABEL org.opencontainers.image.version=\"v2.0.0\" \\\\\n      org.opencontainers.image.title=app\n' > Dockerfile"
+  "bump -in Dockerfile -check | grep '2.0.0'"
+  "bump -in Dockerfile -docker-key=LABEL:org.opencontainers.image.version -check | grep 'v2.0.0'"
+  "bump -in Dockerfile -patch -write"
+  "grep 'ARG VERSION=2.0.1' Dockerfile"
+  "grep 'ENV APP_VERSION=2.0.1' Dockerfile"
+  "grep 'image.version=\"v2.0.1\"' Dockerfile"
+  "rm Dockerfile"
+)
+
+# Scenario 23: Go module major version migration
+declare -a scenario_23=(
+  "mkdir -p gomodule/pkg"
+  "printf 'module example.com/lib\n\ngo 1.24\n' > gomodule/go.mod"
+  "printf 'package pkg\n\nconst X = 1\n' > gomodule/pkg/pkg.go"
+  "printf 'package lib\n\nimport \"example.com/lib/pkg\"\n\nvar Y = pkg.X\n' > gomodule/lib.go"
+  "! bump -in gomodule/go.mod -go-module -minor"
+  "bump -in gomodule/go.mod -go-module -major | grep 'example.com/lib/v2'"
+  "grep 'module example.com/lib$' gomodule/go.mod"
+  "bump -in gomodule/go.mod -go-module -major -write"
+  "grep 'module example.com/lib/v2$' gomodule/go.mod"
+  "grep '\"example.com/lib/v2/pkg\"' gomodule/lib.go"
+  "rm -r gomodule"
+)
+
+# Scenario 24: go.mod Go version sources
+declare -a scenario_24=(
+  "printf 'module myapp\n\ngo 1.22\n\ntoolchain go1.23.4\n' > go.mod"
+  "bump -in go.mod -go=toolchain -fix -write"
+  "grep '^go 1.23.4$' go.mod"
+  "! bump -in go.mod -go=1.24.0 -fix -write"
+  "grep '^go 1.23.4$' go.mod"
+  "printf 'module myapp\n\ngo 1.22\n' > go.mod"
+  "bump -in go.mod -go=local -fix -write"
+  "grep \"^go $(go env GOVERSION | sed 's/^go//')$\" go.mod"
+  "rm go.mod"
+)
+
+# Scenario 25: .bump.yaml and .bump.toml configuration precedence
+declare -a scenario_25=(
+  "mkdir -p configured/sub && echo 'v1.0.0' > configured/VERSION"
+  "printf 'input: VERSION\nchannels: [rc]\nhooks:\n  post: echo \"\$BUMP_OLD_VERSION > \$BUMP_NEW_VERSION\" > hook.log\n' > configured/.bump.yaml"
+  "cd configured/sub && bump -env | grep 'BUMP_CHANNELS=rc # repo config'"
+  "cd configured/sub && BUMP_CHANNELS=alpha,rc bump -env | grep 'BUMP_CHANNELS=alpha,rc # env'"
+  "cd configured/sub && bump -rc -write"
+  "grep 'v1.0.1-rc.1' configured/VERSION"
+  "grep 'v1.0.0 > v1.0.1-rc.1' configured/sub/hook.log"
+  "rm configured/.bump.yaml && printf 'input = \"VERSION\"\nprefix = \"never\"\n' > configured/.bump.toml"
+  "cd configured && bump -check | grep '^1.0.1-rc.1$'"
+  "cd configured && bump -check -in VERSION -env | grep 'BUMP_DEFAULT_INPUT=VERSION # flag'"
+  "rm -r configured"
+)
+
+# Multiple -in files are bumped in lockstep and saved together
+declare -a scenario_26=(
+  "mkdir -p lockstep && echo 'v2.4.0' > lockstep/VERSION && printf '{\n  \"name\": \"app\",\n  \"version\": \"2.4.0\"\n}\n' > lockstep/package.json"
+  "printf 'apiVersion: v2\nname: app\nversion: 2.4.0\n' > lockstep/Chart.yaml"
+  "bump -minor -write -in lockstep/VERSION -in lockstep/package.json -in lockstep/Chart.yaml | grep 'saved to 3 files'"
+  "grep 'v2.5.0' lockstep/VERSION && grep '\"version\": \"2.5.0\"' lockstep/package.json && grep '^version: 2.5.0$' lockstep/Chart.yaml"
+  "bump -rc -json -in lockstep/VERSION,lockstep/Chart.yaml | grep '\"new\": \"2.5.1-rc.1\"'"
+  "! bump -patch -write -in lockstep/VERSION -in lockstep/missing.json"
+  "grep 'v2.5.0' lockstep/VERSION"
+  "rm -r lockstep"
+)
+
+# verify reports drift between version files and sync rewrites them from the -in source
+declare -a scenario_27=(
+  "mkdir -p drift/charts/app && echo 'v3.1.0' > drift/VERSION && printf '{\n  \"version\": \"3.1.0\"\n}\n' > drift/package.json"
+  "printf 'apiVersion: v2\nname: app\nversion: 3.0.9\n' > drift/charts/app/Chart.yaml"
+  "! bump verify drift"
+  "bump verify drift | grep 'charts/app/Chart.yaml: 3.0.9 (drift)'"
+  "bump verify -json drift | grep '\"drift\": true'"
+  "bump sync drift | grep 'use -write to save'"
+  "grep '^version: 3.0.9$' drift/charts/app/Chart.yaml"
+  "bump sync -write drift | grep 'charts/app/Chart.yaml: 3.0.9 → 3.1.0'"
+  "bump verify drift | grep 'All files match v3.1.0 of drift/VERSION'"
+  "bump sync -in drift/package.json -write drift"
+  "rm -r drift"
+)
+
+# workspace discovers packages and bumps them with their dependents
+declare -a scenario_28=(
+  "mkdir -p mono/packages/ui mono/packages/web && printf '{\"private\": true, \"workspaces\": [\"packages/*\"]}\n' > mono/package.json"
+  "printf '{\n  \"name\": \"ui\",\n  \"version\": \"1.2.3\"\n}\n' > mono/packages/ui/package.json"
+  "printf '{\n  \"name\": \"web\",\n  \"version\": \"0.1.0\",\n  \"dependencies\": {\"ui\": \"^1.2.3\"}\n}\n' > mono/packages/web/package.json"
+  "bump workspace mono | grep 'web 0.1.0 (npm, packages/web/package.json) depends on ui'"
+  "bump workspace -package ui -minor mono | grep 'use -write to save'"
+  "bump workspace -package ui -minor -write mono | grep 'packages/web/package.json: ui ^1.2.3 → ^1.3.0'"
+  "grep '\"version\": \"1.3.0\"' mono/packages/ui/package.json && grep '\"ui\": \"^1.3.0\"' mono/packages/web/package.json"
+  "cd mono && git init -q && git add -A && git -c user.name=bump -c user.email=bump@example.com commit -qm init && git tag ui@1.3.0 && git tag web@0.1.0"
+  "bump workspace -changed mono | grep -c . | grep '^0$'"
+  "echo change > mono/packages/web/CHANGELOG && cd mono && git add -A"
+  "bump workspace -changed -patch -json mono | grep '\"tag\": \"web@0.1.1\"'"
+  "rm -rf mono"
+)
+
+# read the version from the highest git tag and tag the bumped version
+declare -a scenario_29=(
+  "mkdir -p tagged && cd tagged && git init -q && git config user.name bump && git config user.email bump@example.com && git commit -q --allow-empty -m init"
+  "cd tagged && git tag v1.2.3 && git tag v1.10.0 && git tag mylib/v0.4.0"
+  "cd tagged && bump -from=git -check | grep '^v1.10.0$'"
+  "cd tagged && bump -from=git -tag-prefix=mylib/v -check | grep '^v0.4.0$'"
+  "cd tagged && bump -from=git -patch -write-tag | grep 'Tagged v1.10.1'"
+  "cd tagged && git cat-file -t v1.10.1 | grep '^tag$'"
+  "cd tagged && bump -from=git -tag-prefix=mylib/v -minor -write-tag -json | grep '\"tag\": \"mylib/v0.5.0\"'"
+  "cd tagged && bump -from=git -release -write-tag 2>&1 | grep 'git tag v1.10.1 already exists'"
+  "cd tagged && echo v1.0.0 > VERSION && bump -from=git -minor -write | grep 'Bumped v1.10.1 → v1.11.0'"
+  "grep '^v1.11.0$' tagged/VERSION"
+  "cd tagged && ! bump -from=git -tag-prefix=other/ -check"
+  "cd tagged && bump -from=git -tag-prefix=other/ -init -minor | grep 'Bumped v0.0.0 → v0.1.0'"
+  "cd tagged && echo v1.0.0 > VERSION && bump -patch -tag 2>&1 | grep -- '-tag needs -write or -commit'"
+  "cd tagged && ! git rev-parse -q --verify refs/tags/v1.0.1 && grep '^v1.0.0$' VERSION"
+  "cd tagged && bump -patch -write -tag | grep 'Tagged v1.0.1' && grep '^v1.0.1$' VERSION"
+  "rm -rf tagged"
+)
+
+# pick the bump level from the Conventional Commits since the last tag
+declare -a scenario_30=(
+  "mkdir -p auto && cd auto && git init -q && git config user.name bump && git config user.email bump@example.com && echo v1.2.3 > VERSION && git add VERSION && git commit -qm 'chore: init' && git tag v1.2.3"
+  "cd auto && git commit -q --allow-empty -m 'docs: readme' && bump -auto | grep 'Version is v1.2.3 (no change)'"
+  "cd auto && git commit -q --allow-empty -m 'fix: crash' && bump -auto | grep 'Bumped v1.2.3 → v1.2.4'"
+  "cd auto && git commit -q --allow-empty -m 'feat(cli): add -auto' && bump -auto | grep 'feat(cli): add -auto (minor)'"
+  "cd auto && BUMP_COMMIT_TYPES=feat=patch,fix=patch bump -auto | grep 'Bumped v1.2.3 → v1.2.4'"
+  "cd auto && git commit -q --allow-empty -m 'feat!: drop -w' && bump -auto -json | grep '\"level\": \"major\"'"
+  "cd auto && bump -auto -write | grep 'Bumped v1.2.3 → v2.0.0'"
+  "cd auto && echo v0.3.1 > VERSION && bump -auto | grep 'Bumped v0.3.1 → v0.4.0'"
+  "cd auto && ! bump -auto -minor"
+  "rm -rf auto"
+)
+
+# promote the [Unreleased] section of a Keep a Changelog file or generate it from the commits
+declare -a scenario_31=(
+  "mkdir -p notes && cd notes && git init -q && git config user.name bump && git config user.email bump@example.com && echo v1.2.3 > VERSION && git add VERSION && git commit -qm 'chore: init' && git tag v1.2.3"
+  "cd notes && git commit -q --allow-empty -m 'feat(cli): add -changelog' && git commit -q --allow-empty -m 'fix: crash'"
+  "cd notes && bump -minor -changelog CHANGELOG.md | grep -- '- \*\*cli:\*\* add -changelog'"
+  "test ! -f notes/CHANGELOG.md"
+  "cd notes && bump -auto -changelog CHANGELOG.md -write | grep 'Updated CHANGELOG.md'"
+  "grep '^v1.3.0$' notes/VERSION && grep '^## \[1.3.0\] - ' notes/CHANGELOG.md && grep '^### Bug Fixes$' notes/CHANGELOG.md"
+  "cd notes && printf '# Changelog\n\n## [Unreleased]\n\n### Added\n\n- By hand\n\n## [1.3.0] - 2026-01-02\n\n[Unreleased]: https://example.com/compare/v1.3.0...HEAD\n' > CHANGELOG.md && git tag v1.3.0"
+  "cd notes && bump -patch -write -json -changelog CHANGELOG.md | grep '\"changelog\": \"## \[1.3.1\]'"
+  "grep -A4 '^## \[1.3.1\]' notes/CHANGELOG.md | grep -- '- By hand'"
+  "grep '^\[1.3.1\]: https://example.com/compare/v1.3.0...v1.3.1$' notes/CHANGELOG.md && grep '^\[Unreleased\]: https://example.com/compare/v1.3.1...HEAD$' notes/CHANGELOG.md"
+  "rm -rf notes"
+)
+
+# commit exactly the files bump modified and tag the commit
+declare -a scenario_32=(
+  "mkdir -p release && cd release && git init -q && git config user.name bump && git config user.email bump@example.com && echo v1.2.3 > VERSION && echo '{\"version\": \"1.2.3\"}' > package.json && echo notes > NOTES && git add -A && git commit -qm init"
+  "cd release && echo more >> NOTES && bump -patch -commit -in VERSION,package.json | grep 'Committed [0-9a-f]* chore(release): v1.2.3 → v1.2.4'"
+  "cd release && git show --name-only --format=%s HEAD | grep -c . | grep '^3$' && git status --short | grep '^ M NOTES$'"
+  "cd release && git add NOTES && ! bump -patch -commit && grep '^v1.2.4$' VERSION && git reset -q"
+  "cd release && bump -minor -commit -tag -commit-message 'release {{.New}}' | grep 'Tagged v1.3.0'"
+  "cd release && git log -1 --format=%s | grep '^release v1.3.0$' && git cat-file -t v1.3.0 | grep '^tag$' && git rev-parse v1.3.0^{commit} | grep \$(git rev-parse HEAD)"
+  "cd release && bump -patch -commit -tag -tag-message '' -json | grep '\"commit\": \"[0-9a-f]*\"'"
+  "cd release && git cat-file -t v1.3.1 | grep '^commit$'"
+  "cd release && ! bump -patch -tag -tag-message '' -sign"
+  "cd release && ! bump -from=git -patch -tag -tag-message 'signed' -sign -json 2>/dev/null | grep '\"tag\"' && ! git rev-parse -q --verify refs/tags/v1.3.2"
+  "rm -rf release"
+)
+
+# promote a version along the channel ladder and refuse to move it back
+declare -a scenario_33=(
+  "echo v1.2.3-alpha.4 > VERSION"
+  "bump -promote | grep 'Bumped v1.2.3-alpha.4 → v1.2.3-beta.1'"
+  "bump -promote -write && bump -promote -write && grep '^v1.2.3-rc.1$' VERSION"
+  "! bump -channel=alpha"
+  "! bump -channel=nightly"
+  "bump -channel=rc | grep 'v1.2.3-rc.1'"
+  "bump -promote -write && grep '^v1.2.3$' VERSION && ! bump -promote"
+  "bump -minor -channel=beta | grep 'Bumped v1.2.3 → v1.3.0-beta.1'"
+  "echo v1.2.3-beta.2 > VERSION && bump -beta | grep 'Bumped v1.2.3-beta.2 → v1.2.3-beta.3'"
+  "echo v2.0.0-dev.3 > VERSION && BUMP_LADDER=dev,rc bump -promote | grep 'Bumped v2.0.0-dev.3 → v2.0.0-rc.1'"
+  "rm VERSION"
+)
+
+# a pre-release of a final version starts the next core version and downgrades are refused
+declare -a scenario_34=(
+  "echo v1.0.0 > VERSION"
+  "bump -alpha | grep 'Bumped v1.0.0 → v1.0.1-alpha.1'"
+  "bump -pre=minor -beta | grep 'Bumped v1.0.0 → v1.1.0-beta.1'"
+  "BUMP_PRE=major bump -rc | grep 'Bumped v1.0.0 → v2.0.0-rc.1'"
+  "bump -major -alpha | grep 'Bumped v1.0.0 → v2.0.0-alpha.1'"
+  "bump -prerelease=nightly.1 | grep 'Bumped v1.0.0 → v1.0.1-nightly.1'"
+  "! bump -pre=none -alpha -write && grep '^v1.0.0$' VERSION"
+  "bump -pre=none -alpha -allow-downgrade | grep 'Bumped v1.0.0 → v1.0.0-alpha.1'"
+  "echo v1.0.0-rc.2 > VERSION && ! bump -alpha && bump -alpha -allow-downgrade | grep 'Bumped v1.0.0-rc.2 → v1.0.0-alpha.1'"
+  "echo v1.0.0-rc.2 > VERSION && ! bump -prerelease=alpha.1 && bump -prerelease=alpha.1 -allow-downgrade | grep 'v1.0.0-alpha.1'"
+  "echo 1.2.3-rc.1 > VERSION && bump -alpha -write 2>&1 | grep '1.2.3-rc.1 → 1.2.3-alpha.1 is a downgrade' && grep '^1.2.3-rc.1$' VERSION"
+  "bump -rc -write | grep 'Bumped 1.2.3-rc.1 → 1.2.3-rc.2' && grep '^1.2.3-rc.2$' VERSION"
+  "echo 1.2.3 > VERSION && bump -alpha -write | grep 'Bumped 1.2.3 → 1.2.4-alpha.1' && grep '^1.2.4-alpha.1$' VERSION"
+  "rm VERSION"
+)
+
 export scenario_01
 export scenario_02
 export scenario_03
@@ -207,3 +485,23 @@ export scenario_11
 export scenario_12
 export scenario_13
 export scenario_14
+export scenario_15
+export scenario_16
+export scenario_17
+export scenario_18
+export scenario_19
+export scenario_20
+export scenario_21
+export scenario_22
+export scenario_23
+export scenario_24
+export scenario_25
+export scenario_26
+export scenario_27
+export scenario_28
+export scenario_29
+export scenario_30
+export scenario_31
+export scenario_32
+export scenario_33
+export scenario_34